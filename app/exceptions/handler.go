@@ -0,0 +1,171 @@
+// Package exceptions turns errors into user-facing responses: Jet error pages
+// for browsers and JSON for API clients. Internal details are only shown when
+// app.debug is enabled, and every 5xx is logged with its request ID.
+package exceptions
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strings"
+
+	"github.com/goravel/fiber"
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/facades"
+	"github.com/goravel/gin"
+	"github.com/samehelhawary/goravel-breeze/app/http/views"
+)
+
+// RequestIDKey is the context key holding the ID of the current request.
+const RequestIDKey = "request_id"
+
+var titles = map[int]string{
+	http.StatusForbidden:           "Forbidden",
+	http.StatusNotFound:            "Not Found",
+	419:                            "Page Expired",
+	http.StatusTooManyRequests:     "Too Many Requests",
+	http.StatusInternalServerError: "Server Error",
+	http.StatusServiceUnavailable:  "Service Unavailable",
+}
+
+// Render responds with the error page for status. The error itself is logged
+// for 5xx statuses and only displayed when app.debug is enabled.
+func Render(ctx http.Context, status int, err error) http.Response {
+	if status >= http.StatusInternalServerError {
+		report(ctx, status, err)
+	}
+
+	return respond(ctx, status, "", err)
+}
+
+// Abort responds with the error page for status and a message that is safe
+// to show to the user.
+func Abort(ctx http.Context, status int, message string) http.Response {
+	return respond(ctx, status, message, nil)
+}
+
+// Halt renders the error page for status from a middleware and stops the
+// request from reaching the next handler.
+func Halt(ctx http.Context, status int, message string) {
+	Send(ctx, Abort(ctx, status, message))
+}
+
+// Send renders response from a middleware and stops the request from reaching
+// the next handler.
+func Send(ctx http.Context, response http.Response) {
+	if err := response.Render(); err != nil {
+		facades.Log().Error("failed to render response: ", err)
+	}
+	if ctx, ok := ctx.(*gin.Context); ok {
+		ctx.Instance().Abort()
+	}
+}
+
+// NotFound can be registered as the route fallback handler.
+func NotFound(ctx http.Context) http.Response {
+	return Abort(ctx, http.StatusNotFound, "")
+}
+
+// Recover can be registered with Route().Recover to render panics as 500 pages.
+func Recover(ctx http.Context, recovered any) {
+	err := fmt.Errorf("panic: %v\n%s", recovered, debug.Stack())
+	if renderErr := Render(ctx, http.StatusInternalServerError, err).Render(); renderErr != nil {
+		facades.Log().Error("failed to render error page: ", renderErr)
+		ctx.Request().Abort(http.StatusInternalServerError)
+	}
+}
+
+// WantsJson reports whether the client expects a JSON response, such as an
+// XHR or API request.
+func WantsJson(ctx http.Context) bool {
+	accept := ctx.Request().Header("Accept")
+
+	return strings.Contains(accept, "/json") ||
+		strings.Contains(accept, "+json") ||
+		ctx.Request().Header("X-Requested-With") == "XMLHttpRequest"
+}
+
+// RequestID returns the ID assigned to the request by the RequestID middleware.
+func RequestID(ctx http.Context) string {
+	id, _ := ctx.Value(RequestIDKey).(string)
+
+	return id
+}
+
+func respond(ctx http.Context, status int, message string, err error) http.Response {
+	if message == "" {
+		message = titles[status]
+	}
+	if message == "" {
+		message = http.StatusText(status)
+	}
+
+	detail := ""
+	if err != nil && facades.Config().GetBool("app.debug") {
+		detail = err.Error()
+	}
+
+	if WantsJson(ctx) {
+		body := http.Json{
+			"error":      message,
+			"request_id": RequestID(ctx),
+		}
+		if detail != "" {
+			body["exception"] = detail
+		}
+
+		return ctx.Response().Json(status, body)
+	}
+
+	view := fmt.Sprintf("errors/%d", status)
+	if !facades.View().Exists(view + ".jet") {
+		view = "errors/500"
+	}
+
+	return &viewResponse{ctx: ctx, status: status, view: view, data: map[string]any{
+		"status":     status,
+		"message":    message,
+		"detail":     detail,
+		"request_id": RequestID(ctx),
+	}}
+}
+
+func report(ctx http.Context, status int, err error) {
+	if err == nil {
+		err = fmt.Errorf("%d %s", status, http.StatusText(status))
+	}
+
+	facades.Log().WithContext(ctx).With(map[string]any{
+		"request_id": RequestID(ctx),
+		"method":     ctx.Request().Method(),
+		"path":       ctx.Request().Path(),
+		"status":     status,
+	}).Error(err)
+}
+
+// viewResponse renders a view with a status code, which View().Make cannot do.
+type viewResponse struct {
+	ctx    http.Context
+	status int
+	view   string
+	data   map[string]any
+}
+
+func (r *viewResponse) Render() error {
+	data := facades.View().GetShared()
+	for key, value := range views.Shared(r.ctx) {
+		data[key] = value
+	}
+	for key, value := range r.data {
+		data[key] = value
+	}
+
+	switch ctx := r.ctx.(type) {
+	case *fiber.Context:
+		return ctx.Instance().Status(r.status).Render(r.view, data)
+	case *gin.Context:
+		ctx.Instance().HTML(r.status, r.view, data)
+		return nil
+	default:
+		return r.ctx.Response().View().Make(r.view, data).Render()
+	}
+}
@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/facades"
+	"github.com/samehelhawary/goravel-breeze/app/exceptions"
+	"github.com/samehelhawary/goravel-breeze/app/http/redirect"
+	"github.com/samehelhawary/goravel-breeze/app/models"
+)
+
+type ImpersonationController struct {
+	// Dependent services
+}
+
+func NewImpersonationController() *ImpersonationController {
+	return &ImpersonationController{
+		// Inject services
+	}
+}
+
+// Start logs the authenticated user in as the user given in the route, keeping
+// the original user ID in the session so the impersonation can be stopped.
+func (r *ImpersonationController) Start(ctx http.Context) http.Response {
+	session := ctx.Request().Session()
+	if session.Get("impersonator_id") != nil {
+		return redirect.New(ctx).Back().With("status", "Stop the current impersonation first").Go()
+	}
+
+	var impersonator, user models.User
+	if err := facades.Orm().Query().Where("id", session.Get("user_id")).First(&impersonator); err != nil {
+		return exceptions.Render(ctx, http.StatusInternalServerError, err)
+	}
+	if err := facades.Orm().Query().Where("id", ctx.Request().Route("id")).First(&user); err != nil || user.ID == 0 {
+		return redirect.New(ctx).Back().With("status", "User not found").Go()
+	}
+	if user.ID == impersonator.ID {
+		return redirect.New(ctx).Back().With("status", "You cannot impersonate yourself").Go()
+	}
+
+	response := facades.Gate().WithContext(ctx).Inspect("impersonate", map[string]any{
+		"impersonator": &impersonator,
+		"user":         &user,
+	})
+	if !response.Allowed() {
+		return exceptions.Abort(ctx, http.StatusForbidden, response.Message())
+	}
+
+	if err := r.audit(ctx, impersonator.ID, user.ID, "start"); err != nil {
+		return exceptions.Render(ctx, http.StatusInternalServerError, err)
+	}
+
+	session.Put("impersonator_id", impersonator.ID)
+	session.Put("user_id", user.ID)
+	if err := regenerateSession(ctx); err != nil {
+		return exceptions.Render(ctx, http.StatusInternalServerError, err)
+	}
+
+	return redirect.New(ctx).To("/dashboard").Go()
+}
+
+// Stop switches the session back to the original user.
+func (r *ImpersonationController) Stop(ctx http.Context) http.Response {
+	session := ctx.Request().Session()
+	impersonatorID := session.Get("impersonator_id")
+	if impersonatorID == nil {
+		return redirect.New(ctx).To("/dashboard").Go()
+	}
+
+	var impersonator, user models.User
+	if err := facades.Orm().Query().Where("id", impersonatorID).First(&impersonator); err != nil || impersonator.ID == 0 {
+		session.Forget("impersonator_id", "user_id")
+		return redirect.New(ctx).To("/login").Go()
+	}
+	_ = facades.Orm().Query().Where("id", session.Get("user_id")).First(&user)
+
+	if err := r.audit(ctx, impersonator.ID, user.ID, "stop"); err != nil {
+		facades.Log().Error("failed to audit impersonation stop: ", err)
+	}
+
+	session.Forget("impersonator_id")
+	session.Put("user_id", impersonator.ID)
+	if err := regenerateSession(ctx); err != nil {
+		return exceptions.Render(ctx, http.StatusInternalServerError, err)
+	}
+
+	return redirect.New(ctx).To("/dashboard").Go()
+}
+
+// audit records an impersonation event in the impersonation_logs table.
+func (r *ImpersonationController) audit(ctx http.Context, impersonatorID, userID uint, action string) error {
+	facades.Log().Infof("Impersonation %s: user %d as user %d.", action, impersonatorID, userID)
+
+	return facades.Orm().Query().Create(&models.ImpersonationLog{
+		ImpersonatorID: impersonatorID,
+		UserID:         userID,
+		Action:         action,
+		IpAddress:      ctx.Request().Ip(),
+		UserAgent:      ctx.Request().Header("User-Agent"),
+	})
+}
@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"fmt"
+
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/contracts/mail"
+	"github.com/goravel/framework/facades"
+	"github.com/samehelhawary/goravel-breeze/app/exceptions"
+	"github.com/samehelhawary/goravel-breeze/app/http/redirect"
+	"github.com/samehelhawary/goravel-breeze/app/http/requests"
+	"github.com/samehelhawary/goravel-breeze/app/http/views"
+	"github.com/samehelhawary/goravel-breeze/app/invitations"
+	"github.com/samehelhawary/goravel-breeze/app/models"
+)
+
+type InvitationController struct {
+	// Dependent services
+}
+
+func NewInvitationController() *InvitationController {
+	return &InvitationController{
+		// Inject services
+	}
+}
+
+func (r *InvitationController) Create(ctx http.Context) http.Response {
+	if _, response := r.authorize(ctx); response != nil {
+		return response
+	}
+
+	return views.Make(ctx, "auth/invitations", map[string]interface{}{
+		"errors": ctx.Request().Session().Get("errors"),
+		"old":    ctx.Request().Session().Get("_old_input"),
+		"mode":   invitations.Mode(),
+	})
+}
+
+// Store issues an invitation and emails the registration link to the invitee.
+// The link is also flashed back so it can be shared by other means.
+func (r *InvitationController) Store(ctx http.Context) http.Response {
+	inviter, response := r.authorize(ctx)
+	if response != nil {
+		return response
+	}
+
+	var storeInvitation requests.StoreInvitationRequest
+	errors, err := ctx.Request().ValidateRequest(&storeInvitation)
+	if err != nil {
+		return exceptions.Render(ctx, http.StatusInternalServerError, err)
+	}
+	if errors != nil {
+		return redirect.New(ctx).Back().WithErrors(errors.All()).WithInput().Go()
+	}
+
+	token, err := invitations.Issue(storeInvitation.Email, &inviter.ID)
+	if err != nil {
+		return exceptions.Render(ctx, http.StatusInternalServerError, err)
+	}
+
+	link := invitations.URL(token)
+	err = facades.Mail().To([]string{storeInvitation.Email}).
+		Subject("You have been invited to " + facades.Config().GetString("app.name")).
+		Content(mail.Content{Html: fmt.Sprintf(`<p>You have been invited to create an account.</p><p><a href="%s">Accept the invitation</a></p>`, link)}).
+		Send()
+	if err != nil {
+		facades.Log().Error("failed to send invitation email: ", err)
+	}
+
+	return redirect.New(ctx).To("/invitations/create").With("invitation_link", link).Go()
+}
+
+// authorize loads the authenticated user and returns a forbidden response
+// unless they pass the "invite" gate.
+func (r *InvitationController) authorize(ctx http.Context) (*models.User, http.Response) {
+	var user models.User
+	if err := facades.Orm().Query().Where("id", ctx.Request().Session().Get("user_id")).First(&user); err != nil {
+		return nil, exceptions.Render(ctx, http.StatusInternalServerError, err)
+	}
+
+	response := facades.Gate().WithContext(ctx).Inspect("invite", map[string]any{
+		"user": &user,
+	})
+	if !response.Allowed() {
+		return nil, exceptions.Abort(ctx, http.StatusForbidden, response.Message())
+	}
+
+	return &user, nil
+}
@@ -0,0 +1,421 @@
+package auth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/facades"
+	"github.com/goravel/framework/support/carbon"
+	"github.com/samehelhawary/goravel-breeze/app/exceptions"
+	"github.com/samehelhawary/goravel-breeze/app/http/redirect"
+	"github.com/samehelhawary/goravel-breeze/app/http/views"
+	"github.com/samehelhawary/goravel-breeze/app/models"
+)
+
+const (
+	registrationCeremonyKey = "webauthn_registration"
+	loginCeremonyKey        = "webauthn_login"
+	pendingUserKey          = "webauthn_pending_user_id"
+	pendingRememberKey      = "webauthn_pending_remember"
+
+	passkeyFailed passkeyFailure = "The passkey could not be verified, please try again."
+)
+
+type PasskeyController struct {
+	// Dependent services
+	users userStore
+}
+
+func NewPasskeyController() *PasskeyController {
+	return &PasskeyController{
+		// Inject services
+		users: ormUserStore{},
+	}
+}
+
+// Index lists the passkeys registered by the authenticated user.
+func (r *PasskeyController) Index(ctx http.Context) http.Response {
+	passkeys, err := r.users.Passkeys(ctx.Request().Session().Get("user_id"), true)
+	if err != nil {
+		return exceptions.Render(ctx, http.StatusInternalServerError, err)
+	}
+
+	return views.Make(ctx, "auth/passkeys", map[string]interface{}{
+		"passkeys": passkeys,
+	})
+}
+
+// RegisterOptions starts the registration ceremony for the authenticated user.
+func (r *PasskeyController) RegisterOptions(ctx http.Context) http.Response {
+	web, err := newWebAuthn()
+	if err != nil {
+		return passkeyError(ctx, err)
+	}
+
+	user, err := r.findPasskeyUser(ctx.Request().Session().Get("user_id"))
+	if err != nil {
+		return passkeyError(ctx, err)
+	}
+
+	creation, session, err := web.BeginRegistration(user,
+		webauthn.WithExclusions(webauthn.Credentials(user.WebAuthnCredentials()).CredentialDescriptors()),
+		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementPreferred),
+	)
+	if err != nil {
+		return passkeyError(ctx, err)
+	}
+
+	if err = putCeremony(ctx, registrationCeremonyKey, session); err != nil {
+		return passkeyError(ctx, err)
+	}
+
+	return ctx.Response().Success().Json(creation)
+}
+
+// Register finishes the registration ceremony and stores the new credential.
+func (r *PasskeyController) Register(ctx http.Context) http.Response {
+	session, err := pullCeremony(ctx, registrationCeremonyKey)
+	if err != nil {
+		return passkeyError(ctx, err)
+	}
+
+	web, err := newWebAuthn()
+	if err != nil {
+		return passkeyError(ctx, err)
+	}
+
+	user, err := r.findPasskeyUser(ctx.Request().Session().Get("user_id"))
+	if err != nil {
+		return passkeyError(ctx, err)
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBody(ctx.Request().Origin().Body)
+	if err != nil {
+		return passkeyError(ctx, err)
+	}
+
+	credential, err := web.CreateCredential(user, *session, parsed)
+	if err != nil {
+		return passkeyError(ctx, err)
+	}
+
+	name := strings.TrimSpace(ctx.Request().Query("name"))
+	if name == "" {
+		name = "Passkey"
+	}
+
+	transports := make([]string, 0, len(credential.Transport))
+	for _, transport := range credential.Transport {
+		transports = append(transports, string(transport))
+	}
+
+	passkey := models.WebauthnCredential{
+		UserID:          user.ID,
+		Name:            name,
+		CredentialID:    base64.RawURLEncoding.EncodeToString(credential.ID),
+		CredentialHash:  models.CredentialHash(credential.ID),
+		PublicKey:       base64.RawURLEncoding.EncodeToString(credential.PublicKey),
+		AttestationType: credential.AttestationType,
+		Transports:      strings.Join(transports, ","),
+		AAGUID:          base64.RawURLEncoding.EncodeToString(credential.Authenticator.AAGUID),
+		SignCount:       credential.Authenticator.SignCount,
+		BackupEligible:  credential.Flags.BackupEligible,
+		BackupState:     credential.Flags.BackupState,
+	}
+	if err = r.users.CreatePasskey(&passkey); err != nil {
+		facades.Log().Error("failed to store passkey: ", err)
+		return passkeyError(ctx, passkeyFailure("The passkey could not be saved."))
+	}
+
+	return ctx.Response().Success().Json(http.Json{
+		"success": true,
+	})
+}
+
+// Destroy removes one of the authenticated user's passkeys.
+func (r *PasskeyController) Destroy(ctx http.Context) http.Response {
+	if err := r.users.DeletePasskey(ctx.Request().Session().Get("user_id"), ctx.Request().Route("id")); err != nil {
+		return exceptions.Render(ctx, http.StatusInternalServerError, err)
+	}
+
+	return redirect.New(ctx).To("/passkeys").With("status", "Passkey removed").Go()
+}
+
+// LoginOptions starts a passwordless login ceremony. The browser picks one of
+// the discoverable credentials it holds for this relying party.
+func (r *PasskeyController) LoginOptions(ctx http.Context) http.Response {
+	web, err := newWebAuthn()
+	if err != nil {
+		return passkeyError(ctx, err)
+	}
+
+	assertion, session, err := web.BeginDiscoverableLogin()
+	if err != nil {
+		return passkeyError(ctx, err)
+	}
+
+	if err = putCeremony(ctx, loginCeremonyKey, session); err != nil {
+		return passkeyError(ctx, err)
+	}
+
+	return ctx.Response().Success().Json(assertion)
+}
+
+// Login finishes a passwordless login ceremony.
+func (r *PasskeyController) Login(ctx http.Context) http.Response {
+	session, err := pullCeremony(ctx, loginCeremonyKey)
+	if err != nil {
+		return passkeyError(ctx, err)
+	}
+
+	web, err := newWebAuthn()
+	if err != nil {
+		return passkeyError(ctx, err)
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBody(ctx.Request().Origin().Body)
+	if err != nil {
+		return passkeyError(ctx, err)
+	}
+
+	found, credential, err := web.ValidatePasskeyLogin(func(rawID, userHandle []byte) (webauthn.User, error) {
+		return r.findPasskeyUser(string(userHandle))
+	}, *session, parsed)
+	if err != nil {
+		return passkeyError(ctx, err)
+	}
+
+	return r.completeLogin(ctx, found.(*passkeyUser), credential, false)
+}
+
+// Challenge shows the second factor prompt to a user that has just signed in
+// with their password.
+func (r *PasskeyController) Challenge(ctx http.Context) http.Response {
+	if ctx.Request().Session().Get(pendingUserKey) == nil {
+		return redirect.New(ctx).To("/login").Go()
+	}
+
+	return views.Make(ctx, "auth/passkey_challenge")
+}
+
+// ChallengeOptions starts a login ceremony restricted to the passkeys of the
+// user waiting for the second factor.
+func (r *PasskeyController) ChallengeOptions(ctx http.Context) http.Response {
+	web, err := newWebAuthn()
+	if err != nil {
+		return passkeyError(ctx, err)
+	}
+
+	user, err := r.findPasskeyUser(ctx.Request().Session().Get(pendingUserKey))
+	if err != nil {
+		return passkeyError(ctx, err)
+	}
+
+	assertion, session, err := web.BeginLogin(user)
+	if err != nil {
+		return passkeyError(ctx, err)
+	}
+
+	if err = putCeremony(ctx, loginCeremonyKey, session); err != nil {
+		return passkeyError(ctx, err)
+	}
+
+	return ctx.Response().Success().Json(assertion)
+}
+
+// ChallengeVerify finishes the second factor ceremony.
+func (r *PasskeyController) ChallengeVerify(ctx http.Context) http.Response {
+	session, err := pullCeremony(ctx, loginCeremonyKey)
+	if err != nil {
+		return passkeyError(ctx, err)
+	}
+
+	web, err := newWebAuthn()
+	if err != nil {
+		return passkeyError(ctx, err)
+	}
+
+	user, err := r.findPasskeyUser(ctx.Request().Session().Get(pendingUserKey))
+	if err != nil {
+		return passkeyError(ctx, err)
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBody(ctx.Request().Origin().Body)
+	if err != nil {
+		return passkeyError(ctx, err)
+	}
+
+	credential, err := web.ValidateLogin(user, *session, parsed)
+	if err != nil {
+		return passkeyError(ctx, err)
+	}
+
+	// The password step left the "remember me" choice for this step to apply
+	remember, _ := ctx.Request().Session().Get(pendingRememberKey).(bool)
+
+	return r.completeLogin(ctx, user, credential, remember)
+}
+
+// completeLogin persists the new signature counter and logs the user in,
+// unless the counter indicates that the authenticator may have been cloned.
+func (r *PasskeyController) completeLogin(ctx http.Context, user *passkeyUser, credential *webauthn.Credential, remember bool) http.Response {
+	credentialID := base64.RawURLEncoding.EncodeToString(credential.ID)
+
+	if credential.Authenticator.CloneWarning {
+		if err := r.users.UpdatePasskey(user.ID, credential.ID, map[string]any{"clone_warning": true}); err != nil {
+			facades.Log().Error("failed to flag cloned passkey: ", err)
+		}
+		facades.Log().Warningf("Possible cloned passkey %s for user %d: signature counter did not increase.", credentialID, user.ID)
+
+		return passkeyError(ctx, passkeyFailure("This passkey can no longer be used, please sign in with your password."))
+	}
+
+	if err := r.users.UpdatePasskey(user.ID, credential.ID, map[string]any{
+		"sign_count":   credential.Authenticator.SignCount,
+		"backup_state": credential.Flags.BackupState,
+		"last_used_at": carbon.NewDateTime(carbon.Now()),
+	}); err != nil {
+		facades.Log().Error("failed to update passkey: ", err)
+	}
+
+	session := ctx.Request().Session()
+	session.Forget(pendingUserKey, pendingRememberKey)
+	if err := regenerateSession(ctx); err != nil {
+		return passkeyError(ctx, err)
+	}
+	session.Put("user_id", user.ID)
+	if remember {
+		rememberUser(ctx, r.users, user.ID)
+	}
+
+	return ctx.Response().Success().Json(http.Json{
+		"success":  true,
+		"redirect": "/dashboard",
+	})
+}
+
+// passkeyUser adapts a user and their stored credentials to webauthn.User.
+type passkeyUser struct {
+	models.User
+	credentials []webauthn.Credential
+}
+
+func (r *PasskeyController) findPasskeyUser(id any) (*passkeyUser, error) {
+	if id == nil || id == "" {
+		return nil, errors.New("no user to authenticate")
+	}
+
+	user, err := r.users.FindUser(id)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	passkeys, err := r.users.Passkeys(user.ID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials := make([]webauthn.Credential, 0, len(passkeys))
+	for _, passkey := range passkeys {
+		credential, err := passkey.ToCredential()
+		if err != nil {
+			facades.Log().Warningf("Skipping unreadable passkey %d: %v", passkey.ID, err)
+			continue
+		}
+		credentials = append(credentials, credential)
+	}
+
+	return &passkeyUser{User: user, credentials: credentials}, nil
+}
+
+func (u *passkeyUser) WebAuthnID() []byte {
+	return []byte(strconv.FormatUint(uint64(u.ID), 10))
+}
+
+func (u *passkeyUser) WebAuthnName() string {
+	return u.Email
+}
+
+func (u *passkeyUser) WebAuthnDisplayName() string {
+	return u.Name
+}
+
+func (u *passkeyUser) WebAuthnCredentials() []webauthn.Credential {
+	return u.credentials
+}
+
+func newWebAuthn() (*webauthn.WebAuthn, error) {
+	var origins []string
+	for _, origin := range strings.Split(facades.Config().GetString("breeze.webauthn.rp_origins"), ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+
+	return webauthn.New(&webauthn.Config{
+		RPID:          facades.Config().GetString("breeze.webauthn.rp_id"),
+		RPDisplayName: facades.Config().GetString("breeze.webauthn.rp_display_name"),
+		RPOrigins:     origins,
+	})
+}
+
+// putCeremony keeps the ceremony state in the session until the browser
+// answers the challenge.
+func putCeremony(ctx http.Context, key string, session *webauthn.SessionData) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	ctx.Request().Session().Put(key, string(data))
+
+	return nil
+}
+
+// pullCeremony retrieves and removes the ceremony state, so every challenge
+// can only be answered once.
+func pullCeremony(ctx http.Context, key string) (*webauthn.SessionData, error) {
+	data, ok := ctx.Request().Session().Pull(key).(string)
+	if !ok || data == "" {
+		return nil, errors.New("no passkey ceremony in progress")
+	}
+
+	var session webauthn.SessionData
+	if err := json.Unmarshal([]byte(data), &session); err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// passkeyFailure is an error whose message is meant for the user.
+type passkeyFailure string
+
+func (f passkeyFailure) Error() string {
+	return string(f)
+}
+
+// passkeyError answers a failed ceremony. Only passkeyFailure messages reach
+// the client, other errors are logged and replaced by a generic message.
+func passkeyError(ctx http.Context, err error) http.Response {
+	var failure passkeyFailure
+	var protocolErr *protocol.Error
+	switch {
+	case errors.As(err, &failure):
+	case errors.As(err, &protocolErr):
+		facades.Log().Debugf("passkey ceremony failed: %s (%s)", protocolErr.Details, protocolErr.DevInfo)
+		failure = passkeyFailed
+	default:
+		facades.Log().Warning("passkey ceremony failed: ", err)
+		failure = passkeyFailed
+	}
+
+	return ctx.Response().Json(http.StatusUnprocessableEntity, http.Json{
+		"error": failure.Error(),
+	})
+}
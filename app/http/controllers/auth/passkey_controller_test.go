@@ -0,0 +1,464 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/contracts/route"
+	"github.com/goravel/framework/contracts/session"
+	"github.com/goravel/framework/support/carbon"
+	"github.com/samehelhawary/goravel-breeze/app/http/middleware"
+	"github.com/samehelhawary/goravel-breeze/app/models"
+	"github.com/samehelhawary/goravel-breeze/testkit"
+)
+
+func TestPasskeyRegistrationAndLogin(t *testing.T) {
+	for _, driver := range testkit.Drivers {
+		t.Run(string(driver), func(t *testing.T) {
+			users := newMemoryUsers()
+			key := newSoftwareAuthenticator(t)
+			registerPasskey(t, newPasskeyClient(t, driver, users, map[string]any{"user_id": 1}), key)
+
+			passkeys, _ := users.Passkeys(1, true)
+			if len(passkeys) != 1 {
+				t.Fatalf("stored %d passkeys, want 1", len(passkeys))
+			}
+			if passkeys[0].CredentialHash != models.CredentialHash(key.id) || len(passkeys[0].CredentialHash) != 64 {
+				t.Errorf("credential hash = %q, want the SHA-256 of the credential ID", passkeys[0].CredentialHash)
+			}
+
+			// A new browser signs in with the passkey alone
+			browser := newPasskeyClient(t, driver, users, nil)
+			options := browser.PostJSON("/passkeys/login/options", "{}")
+			if options.StatusCode != http.StatusOK {
+				t.Fatalf("login options: %d %s", options.StatusCode, options.Body)
+			}
+
+			key.counter = 2
+			before := browser.Cookie(testkit.SessionCookie)
+			response := browser.PostJSON("/passkeys/login", key.assert(t, options.Body))
+			if response.StatusCode != http.StatusOK {
+				t.Fatalf("login: %d %s", response.StatusCode, response.Body)
+			}
+			if after := browser.Cookie(testkit.SessionCookie); after == "" || after == before {
+				t.Errorf("session ID %q was kept on login, want a new one", before)
+			}
+			if userID := browser.Session().Get("user_id"); fmt.Sprint(userID) != "1" {
+				t.Errorf("user_id = %v, want 1", userID)
+			}
+			if passkey := users.passkey(key.id); passkey.SignCount != 2 || passkey.LastUsedAt == nil {
+				t.Errorf("sign count = %d, last used = %v, want 2 and a time", passkey.SignCount, passkey.LastUsedAt)
+			}
+		})
+	}
+}
+
+func TestPasskeyCloneDetection(t *testing.T) {
+	for _, driver := range testkit.Drivers {
+		t.Run(string(driver), func(t *testing.T) {
+			users := newMemoryUsers()
+			key := newSoftwareAuthenticator(t)
+			key.counter = 5
+			registerPasskey(t, newPasskeyClient(t, driver, users, map[string]any{"user_id": 1}), key)
+
+			// The signature counter of a copy of the key lags behind
+			browser := newPasskeyClient(t, driver, users, nil)
+			options := browser.PostJSON("/passkeys/login/options", "{}")
+			key.counter = 3
+			response := browser.PostJSON("/passkeys/login", key.assert(t, options.Body))
+
+			if response.StatusCode != http.StatusUnprocessableEntity || !strings.Contains(response.Body, "can no longer be used") {
+				t.Fatalf("login with a cloned passkey: %d %s, want 422 and the reason", response.StatusCode, response.Body)
+			}
+			if userID := browser.Session().Get("user_id"); userID != nil {
+				t.Errorf("user %v was logged in with a cloned passkey", userID)
+			}
+			if passkey := users.passkey(key.id); !passkey.CloneWarning || passkey.SignCount != 5 {
+				t.Errorf("clone warning = %v, sign count = %d, want true and 5", passkey.CloneWarning, passkey.SignCount)
+			}
+
+			// The flagged passkey is refused from then on, even with a higher counter
+			options = browser.PostJSON("/passkeys/login/options", "{}")
+			key.counter = 10
+			response = browser.PostJSON("/passkeys/login", key.assert(t, options.Body))
+			if response.StatusCode != http.StatusUnprocessableEntity {
+				t.Fatalf("login with a flagged passkey: %d %s, want 422", response.StatusCode, response.Body)
+			}
+		})
+	}
+}
+
+func TestPasskeySecondFactorAppliesRememberMe(t *testing.T) {
+	for _, driver := range testkit.Drivers {
+		for _, remember := range []bool{true, false} {
+			t.Run(fmt.Sprintf("%s/remember=%v", driver, remember), func(t *testing.T) {
+				users := newMemoryUsers()
+				key := newSoftwareAuthenticator(t)
+				registerPasskey(t, newPasskeyClient(t, driver, users, map[string]any{"user_id": 1}), key)
+
+				// The password step stops here when the user has a passkey
+				browser := newPasskeyClient(t, driver, users, map[string]any{
+					pendingUserKey:     1,
+					pendingRememberKey: remember,
+				})
+				options := browser.PostJSON("/passkeys/challenge/options", "{}")
+				if options.StatusCode != http.StatusOK {
+					t.Fatalf("challenge options: %d %s", options.StatusCode, options.Body)
+				}
+
+				key.counter = 1
+				response := browser.PostJSON("/passkeys/challenge", key.assert(t, options.Body))
+				if response.StatusCode != http.StatusOK {
+					t.Fatalf("challenge: %d %s", response.StatusCode, response.Body)
+				}
+
+				session := browser.Session()
+				if fmt.Sprint(session.Get("user_id")) != "1" || session.Has(pendingUserKey) || session.Has(pendingRememberKey) {
+					t.Errorf("session after the challenge = %v", session.All())
+				}
+
+				cookie := browser.Cookie(rememberCookie)
+				if remember && (cookie == "" || cookie != users.rememberToken(1)) {
+					t.Errorf("remember cookie = %q, stored token = %q, want the same token", cookie, users.rememberToken(1))
+				}
+				if !remember && (cookie != "" || users.rememberToken(1) != "") {
+					t.Errorf("user was remembered without asking: cookie %q", cookie)
+				}
+			})
+		}
+	}
+}
+
+func TestPasskeyErrorsHideTheirDetails(t *testing.T) {
+	for _, driver := range testkit.Drivers {
+		t.Run(string(driver), func(t *testing.T) {
+			browser := newPasskeyClient(t, driver, newMemoryUsers(), nil)
+
+			// No ceremony was started, and the body is not a credential
+			for _, path := range []string{"/passkeys/login", "/passkeys/challenge"} {
+				response := browser.PostJSON(path, `{"id":"forged"}`)
+				if response.StatusCode != http.StatusUnprocessableEntity {
+					t.Fatalf("%s: %d %s, want 422", path, response.StatusCode, response.Body)
+				}
+				if !strings.Contains(response.Body, string(passkeyFailed)) {
+					t.Errorf("%s answered %s, want the generic message", path, response.Body)
+				}
+			}
+
+			options := browser.PostJSON("/passkeys/login/options", "{}")
+			if options.StatusCode != http.StatusOK {
+				t.Fatalf("login options: %d %s", options.StatusCode, options.Body)
+			}
+			response := browser.PostJSON("/passkeys/login", `{"id":"forged","type":"public-key"}`)
+			if !strings.Contains(response.Body, string(passkeyFailed)) {
+				t.Errorf("invalid credential answered %s, want the generic message", response.Body)
+			}
+		})
+	}
+}
+
+// registerPasskey runs the registration ceremony for the logged in user.
+func registerPasskey(t *testing.T, client *passkeyClient, key *softwareAuthenticator) {
+	t.Helper()
+
+	options := client.PostJSON("/passkeys/register/options", "{}")
+	if options.StatusCode != http.StatusOK {
+		t.Fatalf("register options: %d %s", options.StatusCode, options.Body)
+	}
+
+	response := client.PostJSON("/passkeys/register?name=Laptop", key.register(t, options.Body))
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("register: %d %s", response.StatusCode, response.Body)
+	}
+}
+
+// passkeyClient is a browser talking to the passkey routes.
+type passkeyClient struct {
+	*testkit.Client
+	sessions *testkit.Sessions
+}
+
+// newPasskeyClient serves the passkey routes with users as the store. Every
+// new session starts with the given values.
+func newPasskeyClient(t *testing.T, driver testkit.Driver, users userStore, session map[string]any) *passkeyClient {
+	app := testkit.New(t, map[string]any{
+		"breeze.webauthn.rp_id":           "localhost",
+		"breeze.webauthn.rp_display_name": "Breeze",
+		"breeze.webauthn.rp_origins":      testkit.BaseURL,
+	})
+
+	sessions := testkit.NewSessions(session)
+	controller := &PasskeyController{users: users}
+	router := app.Route(t, driver)
+	router.Middleware(sessions.Middleware(), middleware.AddQueuedCookies()).Group(func(router route.Router) {
+		router.Post("/passkeys/register/options", controller.RegisterOptions)
+		router.Post("/passkeys/register", controller.Register)
+		router.Post("/passkeys/login/options", controller.LoginOptions)
+		router.Post("/passkeys/login", controller.Login)
+		router.Post("/passkeys/challenge/options", controller.ChallengeOptions)
+		router.Post("/passkeys/challenge", controller.ChallengeVerify)
+	})
+
+	return &passkeyClient{Client: testkit.NewClient(t, router), sessions: sessions}
+}
+
+// Session returns the session as the last request left it.
+func (c *passkeyClient) Session() session.Session {
+	return c.sessions.Current()
+}
+
+// softwareAuthenticator is a platform authenticator holding one P-256
+// passkey, answering ceremonies the way a browser would.
+type softwareAuthenticator struct {
+	key        *ecdsa.PrivateKey
+	id         []byte
+	userHandle []byte
+	counter    uint32
+}
+
+func newSoftwareAuthenticator(t *testing.T) *softwareAuthenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id := make([]byte, 32)
+	_, _ = rand.Read(id)
+
+	return &softwareAuthenticator{key: key, id: id}
+}
+
+// register answers a navigator.credentials.create() call with "none"
+// attestation.
+func (a *softwareAuthenticator) register(t *testing.T, options string) string {
+	t.Helper()
+
+	var creation protocol.CredentialCreation
+	if err := json.Unmarshal([]byte(options), &creation); err != nil {
+		t.Fatalf("invalid creation options %s: %v", options, err)
+	}
+	userID, _ := creation.Response.User.ID.(string)
+	a.userHandle, _ = base64.RawURLEncoding.DecodeString(userID)
+
+	point, err := a.key.PublicKey.ECDH()
+	if err != nil {
+		t.Fatal(err)
+	}
+	uncompressed := point.Bytes()
+	publicKey, err := webauthncbor.Marshal(map[int]any{1: 2, 3: -7, -1: 1, -2: uncompressed[1:33], -3: uncompressed[33:]})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authData := a.authenticatorData(creation.Response.RelyingParty.ID, 0x41|0x04)
+	authData = append(authData, make([]byte, 16)...) // AAGUID
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(a.id)))
+	authData = append(authData, a.id...)
+	authData = append(authData, publicKey...)
+
+	attestation, err := webauthncbor.Marshal(map[string]any{"fmt": "none", "attStmt": map[string]any{}, "authData": authData})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return a.credential(map[string]any{
+		"clientDataJSON":    a.clientData("webauthn.create", creation.Response.Challenge),
+		"attestationObject": encode(attestation),
+		"transports":        []string{"internal"},
+	})
+}
+
+// assert answers a navigator.credentials.get() call.
+func (a *softwareAuthenticator) assert(t *testing.T, options string) string {
+	t.Helper()
+
+	var assertion protocol.CredentialAssertion
+	if err := json.Unmarshal([]byte(options), &assertion); err != nil {
+		t.Fatalf("invalid request options %s: %v", options, err)
+	}
+
+	authData := a.authenticatorData(assertion.Response.RelyingPartyID, 0x01|0x04)
+	clientData := a.clientData("webauthn.get", assertion.Response.Challenge)
+	clientDataJSON, _ := base64.RawURLEncoding.DecodeString(clientData)
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	digest := sha256.Sum256(append(bytes.Clone(authData), clientDataHash[:]...))
+
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return a.credential(map[string]any{
+		"clientDataJSON":    clientData,
+		"authenticatorData": encode(authData),
+		"signature":         encode(signature),
+		"userHandle":        encode(a.userHandle),
+	})
+}
+
+func (a *softwareAuthenticator) authenticatorData(rpID string, flags byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	data := append(rpIDHash[:], flags)
+
+	return binary.BigEndian.AppendUint32(data, a.counter)
+}
+
+func (a *softwareAuthenticator) clientData(ceremony string, challenge []byte) string {
+	data, _ := json.Marshal(map[string]any{
+		"type":      ceremony,
+		"challenge": encode(challenge),
+		"origin":    testkit.BaseURL,
+	})
+
+	return encode(data)
+}
+
+func (a *softwareAuthenticator) credential(response map[string]any) string {
+	body, _ := json.Marshal(map[string]any{
+		"id":       encode(a.id),
+		"rawId":    encode(a.id),
+		"type":     "public-key",
+		"response": response,
+	})
+
+	return string(body)
+}
+
+func encode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// memoryUsers keeps a single user, with ID 1, and their passkeys in memory.
+type memoryUsers struct {
+	mu       sync.Mutex
+	passkeys []models.WebauthnCredential
+	tokens   map[uint]string
+}
+
+func newMemoryUsers() *memoryUsers {
+	return &memoryUsers{tokens: map[uint]string{}}
+}
+
+func (m *memoryUsers) FindUser(id any) (models.User, error) {
+	if userID(id) != 1 {
+		return models.User{}, fmt.Errorf("user %v not found", id)
+	}
+
+	user := models.User{Name: "Test User", Email: "test@example.com"}
+	user.ID = 1
+
+	return user, nil
+}
+
+func (m *memoryUsers) Passkeys(id any, all bool) ([]models.WebauthnCredential, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var passkeys []models.WebauthnCredential
+	for _, passkey := range m.passkeys {
+		if passkey.UserID == userID(id) && (all || !passkey.CloneWarning) {
+			passkeys = append(passkeys, passkey)
+		}
+	}
+
+	return passkeys, nil
+}
+
+func (m *memoryUsers) CreatePasskey(passkey *models.WebauthnCredential) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, existing := range m.passkeys {
+		if existing.CredentialHash == passkey.CredentialHash {
+			return fmt.Errorf("duplicate credential %s", passkey.CredentialHash)
+		}
+	}
+	passkey.ID = uint(len(m.passkeys) + 1)
+	m.passkeys = append(m.passkeys, *passkey)
+
+	return nil
+}
+
+func (m *memoryUsers) UpdatePasskey(userID uint, credentialID []byte, values map[string]any) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range m.passkeys {
+		passkey := &m.passkeys[i]
+		if passkey.UserID != userID || passkey.CredentialHash != models.CredentialHash(credentialID) {
+			continue
+		}
+		for column, value := range values {
+			switch column {
+			case "clone_warning":
+				passkey.CloneWarning = value.(bool)
+			case "sign_count":
+				passkey.SignCount = value.(uint32)
+			case "backup_state":
+				passkey.BackupState = value.(bool)
+			case "last_used_at":
+				usedAt := value.(carbon.DateTime)
+				passkey.LastUsedAt = &usedAt
+			default:
+				return fmt.Errorf("unexpected column %s", column)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (m *memoryUsers) DeletePasskey(any, any) error {
+	return nil
+}
+
+func (m *memoryUsers) SetRememberToken(userID uint, token string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.tokens[userID] = token
+
+	return nil
+}
+
+func (m *memoryUsers) passkey(credentialID []byte) models.WebauthnCredential {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, passkey := range m.passkeys {
+		if passkey.CredentialHash == models.CredentialHash(credentialID) {
+			return passkey
+		}
+	}
+
+	return models.WebauthnCredential{}
+}
+
+func (m *memoryUsers) rememberToken(userID uint) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.tokens[userID]
+}
+
+// userID converts an ID the way it comes out of the session, which stores
+// numbers as JSON, to the model's type.
+func userID(id any) uint {
+	parsed, _ := strconv.ParseUint(fmt.Sprint(id), 10, 64)
+
+	return uint(parsed)
+}
@@ -1,10 +1,14 @@
 package auth
 
 import (
+	"github.com/goravel/framework/contracts/database/orm"
 	"github.com/goravel/framework/contracts/http"
 	"github.com/goravel/framework/facades"
+	"github.com/samehelhawary/goravel-breeze/app/exceptions"
 	"github.com/samehelhawary/goravel-breeze/app/http/redirect"
 	"github.com/samehelhawary/goravel-breeze/app/http/requests"
+	"github.com/samehelhawary/goravel-breeze/app/http/views"
+	"github.com/samehelhawary/goravel-breeze/app/invitations"
 	"github.com/samehelhawary/goravel-breeze/app/models"
 )
 
@@ -19,31 +23,62 @@ func NewRegisterController() *RegisterController {
 }
 
 func (r *RegisterController) Index(ctx http.Context) http.Response {
+	var invitation *models.Invitation
+	switch invitations.Mode() {
+	case invitations.ModeClosed:
+		return exceptions.Abort(ctx, http.StatusForbidden, "Registration is currently closed.")
+	case invitations.ModeInviteOnly:
+		var err error
+		if invitation, err = invitations.Find(ctx.Request().Query("token")); err != nil {
+			return exceptions.Abort(ctx, http.StatusForbidden, "Registration is by invitation only: "+err.Error())
+		}
+	}
 
-	return ctx.Response().View().Make("auth/register", map[string]interface{}{
-		"errors": ctx.Request().Session().Get("errors"),
-		"old":    ctx.Request().Session().Get("_old_input"),
-	})
+	data := map[string]interface{}{
+		"errors":           ctx.Request().Session().Get("errors"),
+		"old":              ctx.Request().Session().Get("_old_input"),
+		"invitation_email": "",
+		"invitation_token": "",
+		"login_identifier": requests.LoginIdentifier(),
+	}
+	if invitation != nil {
+		data["invitation_email"] = invitation.Email
+		data["invitation_token"] = ctx.Request().Query("token")
+	}
+
+	return views.Make(ctx, "auth/register", data)
 }
 
 func (r *RegisterController) Store(ctx http.Context) http.Response {
+	var invitation *models.Invitation
+	switch invitations.Mode() {
+	case invitations.ModeClosed:
+		return redirect.New(ctx).Back().With("status", "Registration is currently closed.").Go()
+	case invitations.ModeInviteOnly:
+		var err error
+		if invitation, err = invitations.Find(ctx.Request().Input("invitation_token")); err != nil {
+			return redirect.New(ctx).Back().WithInput().With("status", err.Error()).Go()
+		}
+	}
+
 	var storeRegister requests.StoreRegisterRequest
 	errors, err := ctx.Request().ValidateRequest(&storeRegister)
 	if err != nil {
-		return ctx.Response().View().Make("error", map[string]interface{}{
-			"err": err,
-		})
+		return exceptions.Render(ctx, http.StatusInternalServerError, err)
 	}
 	if errors != nil {
 		return redirect.New(ctx).Back().WithErrors(errors.All()).WithInput().Go()
 	}
 
+	// The invited address is locked, whatever the submitted form says
+	if invitation != nil && storeRegister.Email != invitation.Email {
+		return redirect.New(ctx).Back().WithInput().With("status", "This invitation was issued for another email address.").Go()
+	}
+
 	password, err := facades.Hash().Make(storeRegister.Password)
 
 	if err != nil {
-		return ctx.Response().View().Make("error", map[string]interface{}{
-			"err": err,
-		})
+		return exceptions.Render(ctx, http.StatusInternalServerError, err)
 	}
 
 	user := models.User{
@@ -51,18 +86,31 @@ func (r *RegisterController) Store(ctx http.Context) http.Response {
 		Email:    storeRegister.Email,
 		Password: password,
 	}
+	if storeRegister.Username != "" {
+		user.Username = &storeRegister.Username
+	}
+
+	// The invitation is used up before the account exists, so two requests
+	// racing with the same token cannot both register
+	err = facades.Orm().Transaction(func(tx orm.Query) error {
+		if invitation != nil {
+			if err := invitations.Accept(tx, invitation); err != nil {
+				return err
+			}
+		}
 
-	if err = facades.Orm().Query().Create(&user); err != nil {
-		return ctx.Response().View().Make("error", map[string]interface{}{
-			"err": err,
-		})
+		return tx.Create(&user)
+	})
+	if err == invitations.ErrInvalid {
+		return redirect.New(ctx).Back().WithInput().With("status", err.Error()).Go()
+	}
+	if err != nil {
+		return exceptions.Render(ctx, http.StatusInternalServerError, err)
 	}
 
 	var loggedInUser models.User
 	if err = facades.Orm().Query().Where("email", user.Email).First(&loggedInUser); err != nil {
-		return ctx.Response().View().Make("error", map[string]interface{}{
-			"err": err,
-		})
+		return exceptions.Render(ctx, http.StatusInternalServerError, err)
 	}
 
 	// login user functionality
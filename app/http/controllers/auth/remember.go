@@ -0,0 +1,25 @@
+package auth
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/facades"
+	"github.com/goravel/framework/support/str"
+	"github.com/samehelhawary/goravel-breeze/app/http/cookies"
+)
+
+// rememberCookie holds the token that logs the user back in once the session
+// has expired, see the RememberMe middleware.
+const rememberCookie = "remember_me_token"
+
+// rememberUser gives the user a new remember token and queues the long lived
+// cookie carrying it. A token that cannot be saved doesn't block the login,
+// the user is simply not remembered.
+func rememberUser(ctx http.Context, users userStore, userID uint) {
+	token := str.Random(60)
+	if err := users.SetRememberToken(userID, token); err != nil {
+		facades.Log().Error("failed to save remember token: ", err)
+		return
+	}
+
+	cookies.Queue(ctx, rememberCookie, token, facades.Config().GetInt("session.remember_lifetime"))
+}
@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/facades"
+	"github.com/goravel/framework/support/carbon"
+)
+
+// regenerateSession moves the session to a new ID and destroys the old one,
+// so an ID obtained before the signed in user changed is useless after it.
+// StartSession has already sent the old ID, so the cookie is sent again.
+func regenerateSession(ctx http.Context) error {
+	session := ctx.Request().Session()
+	if err := session.Regenerate(true); err != nil {
+		return err
+	}
+
+	ctx.Response().Cookie(http.Cookie{
+		Name:     session.GetName(),
+		Value:    session.GetID(),
+		Expires:  carbon.Now().AddMinutes(facades.Config().GetInt("session.lifetime")).StdTime(),
+		Path:     facades.Config().GetString("session.path"),
+		Domain:   facades.Config().GetString("session.domain"),
+		Secure:   facades.Config().GetBool("session.secure"),
+		HttpOnly: facades.Config().GetBool("session.http_only"),
+		SameSite: facades.Config().GetString("session.same_site"),
+	})
+
+	return nil
+}
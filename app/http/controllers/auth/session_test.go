@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"fmt"
+	nethttp "net/http"
+	"testing"
+
+	"github.com/goravel/framework/contracts/http"
+	"github.com/samehelhawary/goravel-breeze/testkit"
+)
+
+func TestRegenerateSessionSendsNewID(t *testing.T) {
+	for _, driver := range testkit.Drivers {
+		t.Run(string(driver), func(t *testing.T) {
+			app := testkit.New(t, nil)
+			sessions := testkit.NewSessions(nil)
+			router := app.Route(t, driver)
+			router.Middleware(sessions.Middleware()).Get("/login", func(ctx http.Context) http.Response {
+				ctx.Request().Session().Put("user_id", 1)
+
+				return ctx.Response().String(http.StatusOK, "")
+			})
+			router.Middleware(sessions.Middleware()).Get("/switch", func(ctx http.Context) http.Response {
+				ctx.Request().Session().Put("user_id", 2)
+				if err := regenerateSession(ctx); err != nil {
+					return ctx.Response().String(http.StatusInternalServerError, err.Error())
+				}
+
+				return ctx.Response().String(http.StatusOK, "")
+			})
+			router.Middleware(sessions.Middleware()).Get("/whoami", func(ctx http.Context) http.Response {
+				return ctx.Response().String(http.StatusOK, fmt.Sprint(ctx.Request().Session().Get("user_id")))
+			})
+
+			client := testkit.NewClient(t, router)
+			client.Get("/login")
+			before := client.Cookie(testkit.SessionCookie)
+
+			if response := client.Get("/switch"); response.StatusCode != http.StatusOK {
+				t.Fatalf("switch: %d %s", response.StatusCode, response.Body)
+			}
+			after := client.Cookie(testkit.SessionCookie)
+			if after == "" || after == before {
+				t.Fatalf("session ID %q was kept, want a new one", before)
+			}
+			if response := client.Get("/whoami"); response.Body != "2" {
+				t.Errorf("user with the new session ID = %s, want 2", response.Body)
+			}
+
+			// The ID known before the switch no longer leads anywhere
+			client.SetCookie(&nethttp.Cookie{Name: testkit.SessionCookie, Value: before, Path: "/"})
+			if response := client.Get("/whoami"); response.Body != "<nil>" {
+				t.Errorf("user with the old session ID = %s, want none", response.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"errors"
+
+	"github.com/goravel/framework/facades"
+	"github.com/samehelhawary/goravel-breeze/app/models"
+)
+
+// userStore is what the authentication controllers read and write about
+// users. The ORM backs it in the application; tests swap in their own.
+type userStore interface {
+	// FindUser returns the user with the given ID.
+	FindUser(id any) (models.User, error)
+	// Passkeys lists the user's passkeys, leaving out the ones flagged as
+	// cloned unless all is set.
+	Passkeys(userID any, all bool) ([]models.WebauthnCredential, error)
+	// CreatePasskey stores a newly registered passkey.
+	CreatePasskey(passkey *models.WebauthnCredential) error
+	// UpdatePasskey changes columns of the user's passkey with the given
+	// credential ID.
+	UpdatePasskey(userID uint, credentialID []byte, values map[string]any) error
+	// DeletePasskey removes one of the user's passkeys.
+	DeletePasskey(userID any, id any) error
+	// SetRememberToken replaces the user's remember token.
+	SetRememberToken(userID uint, token string) error
+}
+
+type ormUserStore struct{}
+
+func (ormUserStore) FindUser(id any) (models.User, error) {
+	var user models.User
+	if err := facades.Orm().Query().Where("id", id).First(&user); err != nil {
+		return user, err
+	}
+	if user.ID == 0 {
+		return user, errors.New("user not found")
+	}
+
+	return user, nil
+}
+
+func (ormUserStore) Passkeys(userID any, all bool) ([]models.WebauthnCredential, error) {
+	query := facades.Orm().Query().Where("user_id", userID)
+	if !all {
+		query = query.Where("clone_warning", false)
+	}
+
+	var passkeys []models.WebauthnCredential
+	err := query.Order("id").Find(&passkeys)
+
+	return passkeys, err
+}
+
+func (ormUserStore) CreatePasskey(passkey *models.WebauthnCredential) error {
+	return facades.Orm().Query().Create(passkey)
+}
+
+func (ormUserStore) UpdatePasskey(userID uint, credentialID []byte, values map[string]any) error {
+	_, err := facades.Orm().Query().Model(&models.WebauthnCredential{}).
+		Where("user_id", userID).
+		Where("credential_hash", models.CredentialHash(credentialID)).
+		Update(values)
+
+	return err
+}
+
+func (ormUserStore) DeletePasskey(userID any, id any) error {
+	_, err := facades.Orm().Query().Where("id", id).Where("user_id", userID).Delete(&models.WebauthnCredential{})
+
+	return err
+}
+
+func (ormUserStore) SetRememberToken(userID uint, token string) error {
+	_, err := facades.Orm().Query().Model(&models.User{}).Where("id", userID).Update("remember_token", token)
+
+	return err
+}
@@ -0,0 +1,29 @@
+package controllers
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/samehelhawary/goravel-breeze/app/http/middleware"
+)
+
+type CsrfTokenController struct {
+	// Dependent services
+}
+
+func NewCsrfTokenController() *CsrfTokenController {
+	return &CsrfTokenController{
+		// Inject services
+	}
+}
+
+// Show returns a freshly masked copy of the session's CSRF token, so that
+// long-lived pages can keep their forms valid. GenerateCSRFToken has already
+// refreshed the token and set the XSRF-TOKEN cookie for this request.
+func (r *CsrfTokenController) Show(ctx http.Context) http.Response {
+	token, _ := ctx.Value("csrf_token").(string)
+
+	return ctx.Response().
+		Header("Cache-Control", "no-store").
+		Json(http.StatusOK, http.Json{
+			"token": middleware.MaskToken(token),
+		})
+}
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"github.com/goravel/framework/contracts/http"
+	"github.com/samehelhawary/goravel-breeze/app/http/views"
 )
 
 type DashboardController struct {
@@ -15,5 +16,5 @@ func NewDashboardController() *DashboardController {
 }
 
 func (r *DashboardController) Index(ctx http.Context) http.Response {
-	return ctx.Response().View().Make("dashboard")
+	return views.Make(ctx, "dashboard")
 }
@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/contracts/mail"
+	"github.com/goravel/framework/facades"
+	"github.com/goravel/framework/support/carbon"
+	"github.com/samehelhawary/goravel-breeze/app/exceptions"
+	"github.com/samehelhawary/goravel-breeze/app/http/redirect"
+	"github.com/samehelhawary/goravel-breeze/app/http/requests"
+	"github.com/samehelhawary/goravel-breeze/app/http/signed"
+	"github.com/samehelhawary/goravel-breeze/app/http/views"
+	"github.com/samehelhawary/goravel-breeze/app/models"
+	"github.com/samehelhawary/goravel-breeze/app/rules"
+)
+
+type ProfileController struct {
+	// Dependent services
+}
+
+func NewProfileController() *ProfileController {
+	return &ProfileController{
+		// Inject services
+	}
+}
+
+func (r *ProfileController) Edit(ctx http.Context) http.Response {
+	var user models.User
+	if err := facades.Orm().Query().Where("id", ctx.Request().Session().Get("user_id")).First(&user); err != nil {
+		return exceptions.Render(ctx, http.StatusInternalServerError, err)
+	}
+
+	pendingEmail := ""
+	if user.PendingEmail != nil {
+		pendingEmail = *user.PendingEmail
+	}
+
+	return views.Make(ctx, "profile", map[string]interface{}{
+		"errors":        ctx.Request().Session().Get("errors"),
+		"old":           ctx.Request().Session().Get("_old_input"),
+		"user":          user,
+		"pending_email": pendingEmail,
+	})
+}
+
+// Update saves the profile. A new email address is only stored as pending
+// until it is confirmed through the signed link sent to it.
+func (r *ProfileController) Update(ctx http.Context) http.Response {
+	var updateProfile requests.UpdateProfileRequest
+	errors, err := ctx.Request().ValidateRequest(&updateProfile)
+	if err != nil {
+		return exceptions.Render(ctx, http.StatusInternalServerError, err)
+	}
+	if errors != nil {
+		return redirect.New(ctx).Back().WithErrors(errors.All()).WithInput().Go()
+	}
+
+	var user models.User
+	if err = facades.Orm().Query().Where("id", ctx.Request().Session().Get("user_id")).First(&user); err != nil {
+		return exceptions.Render(ctx, http.StatusInternalServerError, err)
+	}
+
+	if updateProfile.Email == user.Email {
+		// Keeping the current address abandons a pending change, so a link sent
+		// for it can no longer switch the account
+		if _, err = facades.Orm().Query().Model(&user).Update(map[string]any{
+			"name":          updateProfile.Name,
+			"pending_email": nil,
+		}); err != nil {
+			return exceptions.Render(ctx, http.StatusInternalServerError, err)
+		}
+
+		return redirect.New(ctx).To("/profile").With("status", "Profile updated.").Go()
+	}
+
+	if _, err = facades.Orm().Query().Model(&user).Update("name", updateProfile.Name); err != nil {
+		return exceptions.Render(ctx, http.StatusInternalServerError, err)
+	}
+
+	lifetime := facades.Config().GetInt("breeze.email_change.link_lifetime", 60)
+	link, err := signed.URL("/email/confirm", url.Values{
+		"user":  {fmt.Sprint(user.ID)},
+		"email": {updateProfile.Email},
+	}, time.Duration(lifetime)*time.Minute)
+	if err != nil {
+		return exceptions.Render(ctx, http.StatusInternalServerError, err)
+	}
+
+	if _, err = facades.Orm().Query().Model(&user).Update("pending_email", updateProfile.Email); err != nil {
+		return exceptions.Render(ctx, http.StatusInternalServerError, err)
+	}
+
+	r.send(updateProfile.Email, "Confirm your new email address",
+		fmt.Sprintf(`<p>Please confirm that you want to use this address for your account.</p><p><a href="%s">Confirm email address</a></p><p>This link expires in %d minutes.</p>`, link, lifetime))
+	r.send(user.Email, "Your email address is being changed",
+		fmt.Sprintf(`<p>A request was made to change the email address of your account to %s.</p><p>If this wasn't you, please change your password and contact support.</p>`, updateProfile.Email))
+
+	return redirect.New(ctx).To("/profile").With("status", "We sent a confirmation link to "+updateProfile.Email+".").Go()
+}
+
+// ConfirmEmail applies a pending email change from a signed link.
+func (r *ProfileController) ConfirmEmail(ctx http.Context) http.Response {
+	var user models.User
+	if err := facades.Orm().Query().Where("id", ctx.Request().Query("user")).First(&user); err != nil || user.ID == 0 {
+		return redirect.New(ctx).To("/login").With("status", "This confirmation link is invalid.").Go()
+	}
+
+	email := ctx.Request().Query("email")
+	if user.PendingEmail == nil || *user.PendingEmail != email {
+		return redirect.New(ctx).To("/login").With("status", "This confirmation link is no longer valid.").Go()
+	}
+
+	// The address may have been taken since the change was requested
+	if !(&rules.Unique{}).Passes(nil, email, "users", "email", fmt.Sprint(user.ID)) {
+		if _, err := facades.Orm().Query().Model(&user).Update("pending_email", nil); err != nil {
+			facades.Log().Error("failed to clear pending email: ", err)
+		}
+		return redirect.New(ctx).To("/login").With("status", "This email address has already been taken.").Go()
+	}
+
+	if _, err := facades.Orm().Query().Model(&user).Update(map[string]any{
+		"email":             email,
+		"pending_email":     nil,
+		"email_verified_at": carbon.NewDateTime(carbon.Now()),
+	}); err != nil {
+		return exceptions.Render(ctx, http.StatusInternalServerError, err)
+	}
+
+	if ctx.Request().Session().Get("user_id") != nil {
+		return redirect.New(ctx).To("/profile").With("status", "Your email address has been updated.").Go()
+	}
+
+	return redirect.New(ctx).To("/login").With("status", "Your email address has been updated.").Go()
+}
+
+func (r *ProfileController) send(to, subject, html string) {
+	if err := facades.Mail().To([]string{to}).Subject(subject).Content(mail.Content{Html: html}).Send(); err != nil {
+		facades.Log().Errorf("failed to send %q email: %v", subject, err)
+	}
+}
@@ -0,0 +1,78 @@
+// Package cookies provides helpers for reading and writing cookies beyond
+// plain strings. Values pass through the EncryptCookies middleware like any
+// other cookie.
+package cookies
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+
+	"github.com/goravel/framework/contracts/http"
+)
+
+// ErrMissing is returned by Cookie.Get when the request has no such cookie.
+var ErrMissing = errors.New("cookie not found")
+
+// Cookie reads and writes a cookie holding a JSON encoded T, such as a map or
+// a struct of UI preferences, so small values can be kept client-side without
+// session writes. It is the way to store Go values in cookies: the encrypter
+// encrypts the JSON as it is.
+type Cookie[T any] struct {
+	Name string
+	// Minutes the cookie lives for, zero for a browser session cookie.
+	Minutes int
+}
+
+// New returns a typed cookie with the given name and lifetime in minutes.
+func New[T any](name string, minutes int) *Cookie[T] {
+	return &Cookie[T]{Name: name, Minutes: minutes}
+}
+
+// Get decodes the cookie sent with the request.
+func (c *Cookie[T]) Get(ctx http.Context) (T, error) {
+	var value T
+
+	raw := ctx.Request().Cookie(c.Name)
+	if raw == "" {
+		return value, ErrMissing
+	}
+	decoded, err := url.PathUnescape(raw)
+	if err != nil {
+		return value, err
+	}
+	if err := json.Unmarshal([]byte(decoded), &value); err != nil {
+		return value, err
+	}
+
+	return value, nil
+}
+
+// GetOr decodes the cookie, returning fallback when it is missing or invalid.
+func (c *Cookie[T]) GetOr(ctx http.Context, fallback T) T {
+	value, err := c.Get(ctx)
+	if err != nil {
+		return fallback
+	}
+
+	return value
+}
+
+// Set encodes value and adds the cookie to the response, using the session
+// cookie settings for its attributes. The JSON is URL-escaped, so unencrypted
+// cookies stay valid and can be read with decodeURIComponent in JavaScript.
+func (c *Cookie[T]) Set(ctx http.Context, value T) error {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	ctx.Response().Cookie(Make(c.Name, url.PathEscape(string(encoded)), c.Minutes))
+
+	return nil
+}
+
+// Forget expires the cookie.
+func (c *Cookie[T]) Forget(ctx http.Context) {
+	ctx.Response().WithoutCookie(c.Name)
+}
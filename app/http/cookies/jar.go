@@ -0,0 +1,114 @@
+package cookies
+
+import (
+	"sync"
+
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/facades"
+)
+
+// foreverMinutes is the lifetime of cookies queued with Forever, five years.
+const foreverMinutes = 5 * 365 * 24 * 60
+
+const jarKey = "cookie_jar"
+
+// Jar holds the cookies queued during a request. They are added to the
+// response by the AddQueuedCookies middleware, so they pass through
+// EncryptCookies like any other cookie.
+type Jar struct {
+	mu     sync.Mutex
+	queued []http.Cookie
+}
+
+// JarFor returns the request's jar, creating it on first use.
+func JarFor(ctx http.Context) *Jar {
+	if jar, ok := ctx.Value(jarKey).(*Jar); ok {
+		return jar
+	}
+
+	jar := &Jar{}
+	ctx.WithValue(jarKey, jar)
+	return jar
+}
+
+// Make returns a cookie using the session cookie settings for its path,
+// domain, secure, http_only and same_site attributes. Zero minutes makes a
+// browser session cookie.
+func Make(name, value string, minutes int) http.Cookie {
+	return http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     facades.Config().GetString("session.path", "/"),
+		Domain:   facades.Config().GetString("session.domain"),
+		MaxAge:   minutes * 60,
+		Secure:   facades.Config().GetBool("session.secure"),
+		HttpOnly: facades.Config().GetBool("session.http_only", true),
+		SameSite: facades.Config().GetString("session.same_site", "lax"),
+	}
+}
+
+// Queue adds a cookie to the response, replacing one queued earlier with the
+// same name and path.
+func (j *Jar) Queue(cookie http.Cookie) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for i, queued := range j.queued {
+		if queued.Name == cookie.Name && queued.Path == cookie.Path {
+			j.queued = append(j.queued[:i], j.queued[i+1:]...)
+			break
+		}
+	}
+	j.queued = append(j.queued, cookie)
+}
+
+// Unqueue removes a queued cookie.
+func (j *Jar) Unqueue(name string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	queued := j.queued[:0]
+	for _, cookie := range j.queued {
+		if cookie.Name != name {
+			queued = append(queued, cookie)
+		}
+	}
+	j.queued = queued
+}
+
+// Queued returns the cookies queued so far.
+func (j *Jar) Queued() []http.Cookie {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return append([]http.Cookie{}, j.queued...)
+}
+
+// Flush adds the queued cookies to the response and empties the jar.
+func (j *Jar) Flush(ctx http.Context) {
+	j.mu.Lock()
+	queued := j.queued
+	j.queued = nil
+	j.mu.Unlock()
+
+	for _, cookie := range queued {
+		ctx.Response().Cookie(cookie)
+	}
+}
+
+// Queue queues a cookie living for the given minutes.
+func Queue(ctx http.Context, name, value string, minutes int) {
+	JarFor(ctx).Queue(Make(name, value, minutes))
+}
+
+// Forever queues a cookie that lives for five years.
+func Forever(ctx http.Context, name, value string) {
+	JarFor(ctx).Queue(Make(name, value, foreverMinutes))
+}
+
+// Forget queues a cookie that expires the one the browser holds.
+func Forget(ctx http.Context, name string) {
+	cookie := Make(name, "", 0)
+	cookie.MaxAge = -1
+	JarFor(ctx).Queue(cookie)
+}
@@ -0,0 +1,61 @@
+package cookies
+
+import (
+	nethttp "net/http"
+	"testing"
+
+	"github.com/goravel/framework/contracts/http"
+	"github.com/samehelhawary/goravel-breeze/testkit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJarQueuesForeverAndForgottenCookies(t *testing.T) {
+	for _, driver := range testkit.Drivers {
+		t.Run(string(driver), func(t *testing.T) {
+			router := testkit.New(t, nil).Route(t, driver)
+			router.Get("/", func(ctx http.Context) http.Response {
+				Forever(ctx, "locale", "en")
+				Queue(ctx, "theme", "dark", 60)
+				Queue(ctx, "theme", "light", 60)
+				Queue(ctx, "draft", "1", 60)
+				Forget(ctx, "remember_me_token")
+
+				jar := JarFor(ctx)
+				jar.Unqueue("draft")
+				names := make([]string, 0, len(jar.Queued()))
+				for _, cookie := range jar.Queued() {
+					names = append(names, cookie.Name)
+				}
+				assert.Equal(t, []string{"locale", "theme", "remember_me_token"}, names)
+
+				jar.Flush(ctx)
+				assert.Empty(t, jar.Queued())
+				return ctx.Response().String(http.StatusOK, "ok")
+			})
+
+			response := testkit.NewClient(t, router).Get("/")
+			sent := map[string]*nethttp.Cookie{}
+			for _, raw := range response.Header.Values("Set-Cookie") {
+				cookie, err := nethttp.ParseSetCookie(raw)
+				require.NoError(t, err)
+				sent[cookie.Name] = cookie
+			}
+			require.Len(t, sent, 3)
+
+			// Forever lasts five years
+			assert.Equal(t, "en", sent["locale"].Value)
+			assert.Equal(t, 5*365*24*60*60, sent["locale"].MaxAge)
+
+			assert.Equal(t, "light", sent["theme"].Value)
+			assert.Equal(t, 60*60, sent["theme"].MaxAge)
+
+			// Forget sends an empty cookie the browser deletes right away
+			forgotten := sent["remember_me_token"]
+			assert.Empty(t, forgotten.Value)
+			assert.Negative(t, forgotten.MaxAge)
+			assert.Equal(t, "/", forgotten.Path)
+			assert.True(t, forgotten.HttpOnly)
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/samehelhawary/goravel-breeze/app/http/cookies"
+)
+
+// AddQueuedCookies adds the cookies queued with the cookies package to the
+// response. It must run inside EncryptCookies so they are encrypted.
+func AddQueuedCookies() http.Middleware {
+	return func(ctx http.Context) {
+		jar := cookies.JarFor(ctx)
+		flush := beforeHeadersWritten(ctx, func() {
+			jar.Flush(ctx)
+		})
+
+		ctx.Request().Next()
+		flush()
+	}
+}
@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"errors"
+	"expvar"
+	"sync"
+	"time"
+
+	httpContract "github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/facades"
+	"github.com/samehelhawary/goravel-breeze/app/exceptions"
+	"github.com/samehelhawary/goravel-breeze/crypt"
+)
+
+// CookieFailure is the reason EncryptCookies rejected or could not protect a
+// cookie. It extends the decryption failures of the crypt package.
+type CookieFailure = crypt.Failure
+
+const (
+	CookieBadBase64        = crypt.FailureBadBase64
+	CookieBadPayload       = crypt.FailureBadPayload
+	CookieBadTag           = crypt.FailureBadTag
+	CookieBadSerialization = crypt.FailureBadSerialization
+
+	CookieBadPrefix     CookieFailure = "bad_prefix"
+	CookieBadSignature  CookieFailure = "bad_signature"
+	CookieEncryptFailed CookieFailure = "encrypt_failed"
+)
+
+// cookieFailureLogInterval is how often each failure reason is logged at
+// most, so clients sending forged cookies cannot flood the logs.
+const cookieFailureLogInterval = time.Minute
+
+// cookieFailuresVar is the expvar name of the failure counters, so exporters
+// can read them from /debug/vars when that handler is served.
+const cookieFailuresVar = "breeze_cookie_failures"
+
+var (
+	cookieFailures     *expvar.Map
+	cookieFailuresOnce sync.Once
+)
+
+var cookieFailureLog = newFailureLogLimiter(cookieFailureLogInterval)
+
+// failureCounters returns the failure counters by reason, published on first
+// use rather than at init: the copy of this package published into an
+// application is linked next to this one, and expvar names are process wide,
+// so both share the counters.
+func failureCounters() *expvar.Map {
+	cookieFailuresOnce.Do(func() {
+		cookieFailures = publishedMap(cookieFailuresVar)
+	})
+	return cookieFailures
+}
+
+// publishedMap returns the map published under name, publishing it if needed.
+// A name taken by another kind of variable gets a map that is not published.
+func publishedMap(name string) (published *expvar.Map) {
+	if existing := expvar.Get(name); existing != nil {
+		if existing, ok := existing.(*expvar.Map); ok {
+			return existing
+		}
+		return new(expvar.Map)
+	}
+	defer func() {
+		// Another copy of this package published it in the meantime
+		if recover() != nil {
+			if existing, ok := expvar.Get(name).(*expvar.Map); ok {
+				published = existing
+			} else {
+				published = new(expvar.Map)
+			}
+		}
+	}()
+	return expvar.NewMap(name)
+}
+
+// CookieFailureCounts returns the number of cookie failures since startup,
+// by reason.
+func CookieFailureCounts() map[CookieFailure]int64 {
+	counts := make(map[CookieFailure]int64)
+	failureCounters().Do(func(kv expvar.KeyValue) {
+		if counter, ok := kv.Value.(*expvar.Int); ok {
+			counts[CookieFailure(kv.Key)] = counter.Value()
+		}
+	})
+	return counts
+}
+
+// failureReason returns the reason carried by a DecryptException.
+func failureReason(err error) CookieFailure {
+	var decryptErr *crypt.DecryptException
+	if errors.As(err, &decryptErr) && decryptErr.Reason != "" {
+		return decryptErr.Reason
+	}
+	return CookieBadPayload
+}
+
+// reportCookieFailure counts a failure and logs it with the cookie's name and
+// the request ID, unless its reason was logged within the interval.
+func reportCookieFailure(ctx httpContract.Context, name string, reason CookieFailure, err error) {
+	failureCounters().Add(string(reason), 1)
+
+	suppressed, ok := cookieFailureLog.allow(reason, time.Now())
+	if !ok {
+		return
+	}
+
+	logger := facades.Log().WithContext(ctx).With(map[string]any{
+		"cookie":     name,
+		"reason":     string(reason),
+		"request_id": exceptions.RequestID(ctx),
+		"suppressed": suppressed,
+	})
+	if reason == CookieEncryptFailed {
+		logger.Error("could not encrypt cookie: ", err)
+		return
+	}
+	logger.Warning("could not decrypt cookie: ", err)
+}
+
+// failureLogLimiter allows one log entry per reason and interval, counting
+// the entries it suppressed in between.
+type failureLogLimiter struct {
+	mu         sync.Mutex
+	interval   time.Duration
+	last       map[CookieFailure]time.Time
+	suppressed map[CookieFailure]int
+}
+
+func newFailureLogLimiter(interval time.Duration) *failureLogLimiter {
+	return &failureLogLimiter{
+		interval:   interval,
+		last:       make(map[CookieFailure]time.Time),
+		suppressed: make(map[CookieFailure]int),
+	}
+}
+
+// allow reports whether a failure may be logged now, along with the number
+// of failures suppressed since the last one that was.
+func (l *failureLogLimiter) allow(reason CookieFailure, now time.Time) (int, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if last, ok := l.last[reason]; ok && now.Sub(last) < l.interval {
+		l.suppressed[reason]++
+		return 0, false
+	}
+
+	suppressed := l.suppressed[reason]
+	l.last[reason] = now
+	l.suppressed[reason] = 0
+	return suppressed, true
+}
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"expvar"
+	stdhttp "net/http"
+	"testing"
+	"time"
+
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/contracts/log"
+	"github.com/samehelhawary/goravel-breeze/testkit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCookieFailureCountersAreSharedByPublishedCopies(t *testing.T) {
+	counters := failureCounters()
+	assert.Same(t, counters, expvar.Get(cookieFailuresVar))
+
+	// A published copy of this package finds the counters instead of panicking
+	assert.Same(t, counters, publishedMap(cookieFailuresVar))
+
+	fresh := publishedMap("breeze_test_fresh_map")
+	assert.Same(t, fresh, expvar.Get("breeze_test_fresh_map"))
+	assert.Same(t, fresh, publishedMap("breeze_test_fresh_map"))
+
+	taken := expvar.NewInt("breeze_test_taken_name")
+	assert.NotNil(t, publishedMap("breeze_test_taken_name"))
+	assert.Same(t, taken, expvar.Get("breeze_test_taken_name"))
+}
+
+func TestFailureLogLimiterAllowsOneEntryPerReasonAndInterval(t *testing.T) {
+	limiter := newFailureLogLimiter(time.Minute)
+	now := time.Now()
+
+	steps := []struct {
+		reason     CookieFailure
+		at         time.Duration
+		allowed    bool
+		suppressed int
+	}{
+		{CookieBadTag, 0, true, 0},
+		{CookieBadTag, 10 * time.Second, false, 0},
+		{CookieBadTag, 59 * time.Second, false, 0},
+		{CookieBadPrefix, 30 * time.Second, true, 0},
+		{CookieBadTag, time.Minute, true, 2},
+		{CookieBadTag, time.Minute + time.Second, false, 0},
+		{CookieBadTag, 3 * time.Minute, true, 1},
+	}
+
+	for i, step := range steps {
+		suppressed, allowed := limiter.allow(step.reason, now.Add(step.at))
+		assert.Equal(t, step.allowed, allowed, "step %d", i)
+		assert.Equal(t, step.suppressed, suppressed, "step %d", i)
+	}
+}
+
+func TestCookieFailuresAreCountedAndLoggedOncePerInterval(t *testing.T) {
+	previousLog := cookieFailureLog
+	t.Cleanup(func() {
+		cookieFailureLog = previousLog
+	})
+	cookieFailureLog = newFailureLogLimiter(time.Minute)
+
+	app := newApp(t, nil)
+	router := app.Route(t, testkit.Gin)
+	router.Middleware(NewEncryptCookies().Handle()).Get("/", func(ctx http.Context) http.Response {
+		return ctx.Response().String(http.StatusOK, ctx.Request().Cookie("theme"))
+	})
+	client := testkit.NewClient(t, router)
+	client.SetCookie(&stdhttp.Cookie{Name: "theme", Value: "!forged!", Path: "/"})
+
+	failures := CookieFailureCounts()[CookieBadBase64]
+	for range 3 {
+		assert.Empty(t, client.Get("/").Body)
+	}
+	assert.Equal(t, failures+3, CookieFailureCounts()[CookieBadBase64])
+
+	warnings := func() []testkit.Entry {
+		var entries []testkit.Entry
+		for _, entry := range app.Logs().Entries() {
+			if entry.Level == log.WarningLevel && entry.Data["reason"] == string(CookieBadBase64) {
+				entries = append(entries, entry)
+			}
+		}
+		return entries
+	}
+	require.Len(t, warnings(), 1)
+	assert.Equal(t, "theme", warnings()[0].Data["cookie"])
+	assert.Equal(t, 0, warnings()[0].Data["suppressed"])
+
+	// Once the interval has passed, the next failure is logged with the count
+	// of those that were not
+	cookieFailureLog.last[CookieBadBase64] = time.Now().Add(-time.Minute)
+	client.Get("/")
+	require.Len(t, warnings(), 2)
+	assert.Equal(t, 2, warnings()[1].Data["suppressed"])
+}
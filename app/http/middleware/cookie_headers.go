@@ -0,0 +1,188 @@
+package middleware
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	gingonic "github.com/gin-gonic/gin"
+	"github.com/goravel/fiber"
+	httpContract "github.com/goravel/framework/contracts/http"
+	"github.com/goravel/gin"
+	"github.com/valyala/fasthttp"
+)
+
+// requestCookies returns the cookies sent with the request, in order and
+// including duplicate names.
+func requestCookies(ctx httpContract.Context) []*http.Cookie {
+	switch ctx := ctx.(type) {
+	case *fiber.Context:
+		var cookies []*http.Cookie
+		ctx.Instance().Request().Header.VisitAllCookie(func(name, value []byte) {
+			cookies = append(cookies, &http.Cookie{Name: string(name), Value: string(value)})
+		})
+		return cookies
+	case *gin.Context:
+		return ctx.Instance().Request.Cookies()
+	default:
+		return ctx.Request().Origin().Cookies()
+	}
+}
+
+// encodeRequestCookie encodes a decrypted cookie value the way the driver's
+// Request().Cookie decodes it: gin URL-decodes cookie values.
+func encodeRequestCookie(ctx httpContract.Context, value string) string {
+	if _, ok := ctx.(*gin.Context); ok {
+		return url.QueryEscape(value)
+	}
+	return value
+}
+
+// decodeResponseCookie returns the value the application gave to a cookie it
+// set: gin URL-encodes cookie values. The value is encrypted as given, so
+// both drivers and Laravel agree on the plaintext.
+func decodeResponseCookie(ctx httpContract.Context, value string) string {
+	if _, ok := ctx.(*gin.Context); ok {
+		if decoded, err := url.QueryUnescape(value); err == nil {
+			return decoded
+		}
+	}
+	return value
+}
+
+// replaceRequestCookies replaces the cookies read by Request().Cookie. Fiber
+// is updated directly, as its Origin() request is a copy.
+func replaceRequestCookies(ctx httpContract.Context, cookies []*http.Cookie) {
+	pairs := make([]string, len(cookies))
+	for i, cookie := range cookies {
+		pairs[i] = cookie.Name + "=" + cookie.Value
+	}
+
+	switch ctx := ctx.(type) {
+	case *fiber.Context:
+		header := &ctx.Instance().Request().Header
+		header.DelAllCookies()
+		if len(pairs) > 0 {
+			header.Set("Cookie", strings.Join(pairs, "; "))
+		}
+	default:
+		header := ctx.Request().Origin().Header
+		header.Del("Cookie")
+		if len(pairs) > 0 {
+			header.Set("Cookie", strings.Join(pairs, "; "))
+		}
+	}
+}
+
+// responseSetCookies returns the Set-Cookie headers of the response. The
+// drivers are accessed directly, as fiber's Origin().Header() is a copy.
+func responseSetCookies(ctx httpContract.Context) []string {
+	switch ctx := ctx.(type) {
+	case *fiber.Context:
+		var headers []string
+		ctx.Instance().Response().Header.VisitAllCookie(func(_, value []byte) {
+			headers = append(headers, string(value))
+		})
+		return headers
+	case *gin.Context:
+		return append([]string{}, ctx.Instance().Writer.Header()["Set-Cookie"]...)
+	default:
+		return append([]string{}, ctx.Response().Origin().Header()["Set-Cookie"]...)
+	}
+}
+
+// replaceResponseSetCookies replaces the Set-Cookie headers of the response.
+// The headers are sent as they are: fiber is given the raw values, rather
+// than cookies parsed by fasthttp, which would drop what it does not know.
+func replaceResponseSetCookies(ctx httpContract.Context, headers []string) {
+	switch ctx := ctx.(type) {
+	case *fiber.Context:
+		header := &ctx.Instance().Response().Header
+		header.DelAllCookies()
+		for _, raw := range headers {
+			header.Add(fasthttp.HeaderSetCookie, raw)
+		}
+	case *gin.Context:
+		ctx.Instance().Writer.Header()["Set-Cookie"] = headers
+	default:
+		header := ctx.Response().Origin().Header()
+		header.Del("Set-Cookie")
+		for _, raw := range headers {
+			header.Add("Set-Cookie", raw)
+		}
+	}
+}
+
+// beforeHeadersWritten arranges for fn to run once, right before the response
+// headers are sent. Gin sends them while the route handler renders, before
+// middleware regains control, so its writer is wrapped. The returned function
+// runs fn if that has not happened yet.
+func beforeHeadersWritten(ctx httpContract.Context, fn func()) func() {
+	once := &sync.Once{}
+	run := func() { once.Do(fn) }
+	if ctx, ok := ctx.(*gin.Context); ok {
+		instance := ctx.Instance()
+		instance.Writer = &headerHookWriter{ResponseWriter: instance.Writer, hook: run}
+	}
+
+	return run
+}
+
+type headerHookWriter struct {
+	gingonic.ResponseWriter
+	hook func()
+}
+
+func (w *headerHookWriter) Write(data []byte) (int, error) {
+	w.hook()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *headerHookWriter) WriteString(s string) (int, error) {
+	w.hook()
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *headerHookWriter) WriteHeaderNow() {
+	w.hook()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *headerHookWriter) Flush() {
+	w.hook()
+	w.ResponseWriter.Flush()
+}
+
+// formatSetCookie serializes a cookie, keeping attributes net/http does not
+// know about.
+func formatSetCookie(cookie *http.Cookie) string {
+	header := cookie.String()
+	for _, attribute := range cookie.Unparsed {
+		header += "; " + attribute
+	}
+	return header
+}
+
+// mergeSetCookies drops Set-Cookie headers overridden by a later header for
+// the same cookie name, domain and path, as the browser would.
+func mergeSetCookies(headers []string) []string {
+	last := make(map[string]int, len(headers))
+	keys := make([]string, len(headers))
+	for i, raw := range headers {
+		cookie, err := http.ParseSetCookie(raw)
+		if err != nil {
+			continue
+		}
+		keys[i] = cookie.Name + "\x00" + strings.ToLower(cookie.Domain) + "\x00" + cookie.Path
+		last[keys[i]] = i
+	}
+
+	merged := make([]string, 0, len(headers))
+	for i, raw := range headers {
+		if keys[i] == "" || last[keys[i]] == i {
+			merged = append(merged, raw)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/goravel/fiber"
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/gin"
+	"github.com/samehelhawary/goravel-breeze/testkit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// addSetCookie adds a raw Set-Cookie header, for attributes http.Cookie
+// cannot express.
+func addSetCookie(ctx http.Context, raw string) {
+	switch ctx := ctx.(type) {
+	case *gin.Context:
+		ctx.Instance().Writer.Header().Add("Set-Cookie", raw)
+	case *fiber.Context:
+		ctx.Instance().Response().Header.Add("Set-Cookie", raw)
+	}
+}
+
+func TestEncryptCookiesKeepSetCookieAttributes(t *testing.T) {
+	type want struct {
+		name       string
+		value      string
+		deleted    bool
+		attributes []string
+	}
+
+	tests := []struct {
+		name string
+		set  []string
+		want []want
+	}{
+		{
+			name: "same site strict",
+			set:  []string{"theme=dark; Path=/; SameSite=Strict"},
+			want: []want{{name: "theme", value: "dark", attributes: []string{"Path=/", "SameSite=Strict"}}},
+		},
+		{
+			name: "same site none",
+			set:  []string{"theme=dark; Path=/; Secure; SameSite=None"},
+			want: []want{{name: "theme", value: "dark", attributes: []string{"Secure", "SameSite=None"}}},
+		},
+		{
+			name: "partitioned",
+			set:  []string{"theme=dark; Path=/; Secure; SameSite=None; Partitioned"},
+			want: []want{{name: "theme", value: "dark", attributes: []string{"Secure", "SameSite=None", "Partitioned"}}},
+		},
+		{
+			name: "max age and expires",
+			set:  []string{"theme=dark; Path=/; Domain=example.com; Max-Age=3600; Expires=Wed, 21 Oct 2099 07:28:00 GMT; HttpOnly"},
+			want: []want{{name: "theme", value: "dark", attributes: []string{"Domain=example.com", "Max-Age=3600", "Expires=Wed, 21 Oct 2099 07:28:00 GMT", "HttpOnly"}}},
+		},
+		{
+			name: "deletion",
+			set:  []string{"theme=; Path=/; Max-Age=0"},
+			want: []want{{name: "theme", deleted: true, attributes: []string{"Max-Age=0"}}},
+		},
+		{
+			name: "deletion after set",
+			set:  []string{"theme=dark; Path=/", "theme=; Path=/; Max-Age=0"},
+			want: []want{{name: "theme", deleted: true, attributes: []string{"Max-Age=0"}}},
+		},
+		{
+			name: "duplicates merged",
+			set:  []string{"theme=dark; Path=/", "locale=en; Path=/", "theme=light; Path=/; SameSite=Lax"},
+			want: []want{
+				{name: "locale", value: "en", attributes: []string{"Path=/"}},
+				{name: "theme", value: "light", attributes: []string{"SameSite=Lax"}},
+			},
+		},
+		{
+			name: "other paths kept",
+			set:  []string{"theme=dark; Path=/", "theme=light; Path=/admin"},
+			want: []want{
+				{name: "theme", value: "dark", attributes: []string{"Path=/"}},
+				{name: "theme", value: "light", attributes: []string{"Path=/admin"}},
+			},
+		},
+	}
+
+	for _, driver := range testkit.Drivers {
+		for _, test := range tests {
+			t.Run(string(driver)+"/"+test.name, func(t *testing.T) {
+				router := newApp(t, nil).Route(t, driver)
+				cookies := NewEncryptCookies()
+				router.Middleware(cookies.Handle()).Get("/", func(ctx http.Context) http.Response {
+					for _, raw := range test.set {
+						addSetCookie(ctx, raw)
+					}
+					return ctx.Response().String(http.StatusOK, "ok")
+				})
+
+				response := testkit.NewClient(t, router).Get("/")
+				headers := response.Header.Values("Set-Cookie")
+				require.Len(t, headers, len(test.want), headers)
+
+				for i, want := range test.want {
+					raw := headers[i]
+					pair, _, _ := strings.Cut(raw, ";")
+					name, value, _ := strings.Cut(pair, "=")
+					assert.Equal(t, want.name, name, raw)
+
+					if want.deleted {
+						assert.Empty(t, value, raw)
+					} else {
+						assert.NotEqual(t, want.value, value, "the value must be encrypted")
+						decrypted, err := cookies.Decrypt(name, value)
+						assert.NoError(t, err)
+						assert.Equal(t, want.value, decrypted)
+					}
+					for _, attribute := range want.attributes {
+						assert.Contains(t, raw, "; "+attribute, raw)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/support/str"
+)
+
+const routeNameKey = "route_name"
+
+// RouteName names the route it is attached to, so that CSRF exclusions can
+// refer to it. Goravel routes are unnamed, so it must be listed before the
+// CSRF middleware:
+//
+//	facades.Route().Middleware(middleware.RouteName("webhooks.stripe"), middleware.CSRF()).Post(...)
+func RouteName(name string) http.Middleware {
+	return func(ctx http.Context) {
+		ctx.WithValue(routeNameKey, name)
+		ctx.Request().Next()
+	}
+}
+
+// csrfExclusions returns the patterns configured in breeze.csrf.except.
+func csrfExclusions() []string {
+	return configStrings("breeze.csrf.except")
+}
+
+// inExceptArray reports whether the request matches one of the patterns. A
+// pattern containing "://" is matched against the full URL, one containing a
+// "/" against the path, and anything else against the route name. An "*"
+// matches any sequence of characters.
+func inExceptArray(ctx http.Context, patterns []string) bool {
+	if len(patterns) == 0 {
+		return false
+	}
+
+	url, _, _ := strings.Cut(ctx.Request().FullUrl(), "?")
+	path := "/" + strings.Trim(ctx.Request().Path(), "/")
+	name, _ := ctx.Value(routeNameKey).(string)
+
+	for _, pattern := range patterns {
+		switch {
+		case strings.Contains(pattern, "://"):
+			if str.Of(url).Is(pattern) {
+				return true
+			}
+		case strings.Contains(pattern, "/"):
+			if pattern != "/" {
+				pattern = "/" + strings.Trim(pattern, "/")
+			}
+			if str.Of(path).Is(pattern) {
+				return true
+			}
+		default:
+			if name != "" && str.Of(name).Is(pattern) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
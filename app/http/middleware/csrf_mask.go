@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+)
+
+// MaskToken returns the token XORed with a fresh one-time pad, prefixed with
+// the pad. Every render emits a different value for the same token, which
+// defeats BREACH-style compression attacks on pages embedding it.
+func MaskToken(token string) string {
+	pad := make([]byte, len(token))
+	if _, err := rand.Read(pad); err != nil {
+		return token
+	}
+
+	masked := make([]byte, 2*len(token))
+	copy(masked, pad)
+	for i := range token {
+		masked[len(token)+i] = token[i] ^ pad[i]
+	}
+
+	return base64.RawURLEncoding.EncodeToString(masked)
+}
+
+// unmaskToken reverses MaskToken. It reports false when the value is not a
+// masked token.
+func unmaskToken(masked string) (string, bool) {
+	data, err := base64.RawURLEncoding.DecodeString(masked)
+	if err != nil || len(data) == 0 || len(data)%2 != 0 {
+		return "", false
+	}
+
+	size := len(data) / 2
+	token := make([]byte, size)
+	for i := range token {
+		token[i] = data[size+i] ^ data[i]
+	}
+
+	return string(token), true
+}
+
+// tokensMatch compares a submitted token, masked or not, with the stored one
+// in constant time.
+func tokensMatch(stored, submitted string) bool {
+	if stored == "" || submitted == "" {
+		return false
+	}
+
+	if subtle.ConstantTimeCompare([]byte(stored), []byte(submitted)) == 1 {
+		return true
+	}
+
+	token, ok := unmaskToken(submitted)
+
+	return ok && subtle.ConstantTimeCompare([]byte(stored), []byte(token)) == 1
+}
@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/facades"
+	"github.com/goravel/framework/support/str"
+)
+
+// originPolicy rejects unsafe requests that a browser reports as coming from
+// a foreign origin, through the Sec-Fetch-Site, Origin or Referer headers.
+// Origins are compared by scheme, host and port.
+type originPolicy struct {
+	enabled bool
+	trusted []string
+}
+
+func newOriginPolicy() originPolicy {
+	var trusted []string
+	for _, origin := range strings.Split(facades.Config().GetString("breeze.csrf.trusted_origins"), ",") {
+		if origin = originOf(origin); origin != "" {
+			trusted = append(trusted, origin)
+		}
+	}
+	if origin := originOf(facades.Config().GetString("app.url")); origin != "" {
+		trusted = append(trusted, origin)
+	}
+
+	return originPolicy{
+		enabled: facades.Config().GetBool("breeze.csrf.check_origin"),
+		trusted: trusted,
+	}
+}
+
+// allows reports whether the request may proceed. Requests without any of
+// the headers, such as those from non-browser clients, are allowed and left
+// to the token check.
+func (p originPolicy) allows(ctx http.Context) bool {
+	if !p.enabled {
+		return true
+	}
+
+	site := ctx.Request().Header("Sec-Fetch-Site")
+	if site == "same-origin" || site == "none" {
+		return true
+	}
+
+	origin := originHeader(ctx)
+	if origin == "" {
+		// Browsers always send an Origin with cross-site unsafe requests
+		return site == ""
+	}
+
+	return p.trusts(ctx, origin)
+}
+
+// vouches reports whether the browser sent the request from this application
+// or a trusted origin, which CSRFVerifier.OriginOnly accepts without a token.
+// Requests without any of the headers never qualify.
+func (p originPolicy) vouches(ctx http.Context) bool {
+	if ctx.Request().Header("Sec-Fetch-Site") == "same-origin" {
+		return true
+	}
+
+	origin := originHeader(ctx)
+	return origin != "" && p.trusts(ctx, origin)
+}
+
+// trusts reports whether origin is the request's own origin or a trusted
+// one. An opaque "null" origin or a malformed header is never trusted.
+func (p originPolicy) trusts(ctx http.Context, origin string) bool {
+	if origin = originOf(origin); origin == "" {
+		return false
+	}
+	if origin == requestOrigin(ctx) {
+		return true
+	}
+
+	return str.Of(origin).Is(p.trusted...)
+}
+
+// originHeader returns the Origin header, or the Referer when there is none.
+func originHeader(ctx http.Context) string {
+	if origin := ctx.Request().Header("Origin"); origin != "" {
+		return origin
+	}
+
+	return ctx.Request().Header("Referer")
+}
+
+// sameOrigin reports whether rawURL belongs to this application: its scheme,
+// host and port match either the URL the request was sent to or the
+// application URL, which differ behind a TLS terminating proxy.
+func sameOrigin(ctx http.Context, rawURL string) bool {
+	origin := originOf(rawURL)
+	if origin == "" {
+		return false
+	}
+
+	return origin == requestOrigin(ctx) || origin == originOf(facades.Config().GetString("app.url"))
+}
+
+// requestOrigin returns the origin the request was sent to.
+func requestOrigin(ctx http.Context) string {
+	scheme := "http"
+	if strings.HasPrefix(ctx.Request().FullUrl(), "https://") {
+		scheme = "https"
+	}
+
+	return originOf(scheme + "://" + ctx.Request().Host())
+}
+
+// originOf returns the lowercased scheme://host:port of rawURL, with the
+// default port of http and https made explicit, or "" when rawURL is not an
+// absolute http(s) URL. A "*" wildcard in the host is kept, so trusted
+// origin patterns are normalized the same way.
+func originOf(rawURL string) string {
+	parsed, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || parsed.Hostname() == "" {
+		return ""
+	}
+
+	var port string
+	switch strings.ToLower(parsed.Scheme) {
+	case "http":
+		port = "80"
+	case "https":
+		port = "443"
+	default:
+		return ""
+	}
+	if parsed.Port() != "" {
+		port = parsed.Port()
+	}
+
+	return strings.ToLower(parsed.Scheme) + "://" + net.JoinHostPort(strings.ToLower(parsed.Hostname()), port)
+}
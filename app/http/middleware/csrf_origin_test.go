@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/goravel/framework/contracts/http"
+	"github.com/samehelhawary/goravel-breeze/testkit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOriginPolicyComparesSchemeHostAndPort(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		allowed bool
+	}{
+		{"no headers", nil, true},
+		{"same-origin fetch", map[string]string{"Sec-Fetch-Site": "same-origin"}, true},
+		{"cross-site fetch without origin", map[string]string{"Sec-Fetch-Site": "cross-site"}, false},
+		{"request origin", map[string]string{"Origin": "http://localhost"}, true},
+		{"request host on another scheme", map[string]string{"Origin": "https://localhost"}, false},
+		{"request host on another port", map[string]string{"Origin": "http://localhost:8080"}, false},
+		{"application url", map[string]string{"Origin": "https://app.example.com"}, true},
+		{"application url with default port", map[string]string{"Origin": "https://APP.example.com:443"}, true},
+		{"application host on another scheme", map[string]string{"Origin": "http://app.example.com"}, false},
+		{"application host on another port", map[string]string{"Origin": "https://app.example.com:8443"}, false},
+		{"trusted wildcard", map[string]string{"Origin": "https://api.partner.example"}, true},
+		{"trusted wildcard on another scheme", map[string]string{"Origin": "http://api.partner.example"}, false},
+		{"trusted wildcard on another port", map[string]string{"Origin": "https://api.partner.example:8443"}, false},
+		{"trusted port", map[string]string{"Origin": "http://localhost:3000"}, true},
+		{"foreign origin", map[string]string{"Origin": "https://evil.example"}, false},
+		{"opaque origin", map[string]string{"Origin": "null"}, false},
+		{"same origin referer", map[string]string{"Referer": "https://app.example.com/profile"}, true},
+		{"foreign referer", map[string]string{"Referer": "https://evil.example/app.example.com"}, false},
+	}
+
+	for _, driver := range testkit.Drivers {
+		for _, test := range tests {
+			t.Run(string(driver)+"/"+test.name, func(t *testing.T) {
+				app := testkit.New(t, map[string]any{
+					"app.url":                     "https://app.example.com/",
+					"breeze.csrf.check_origin":    true,
+					"breeze.csrf.trusted_origins": "https://*.partner.example, http://localhost:3000/",
+				})
+				policy := newOriginPolicy()
+				router := app.Route(t, driver)
+				router.Post("/profile", func(ctx http.Context) http.Response {
+					return ctx.Response().String(http.StatusOK, strconv.FormatBool(policy.allows(ctx)))
+				})
+
+				request := testkit.NewRequest(http.MethodPost, "/profile", nil)
+				for key, value := range test.headers {
+					request.Header.Set(key, value)
+				}
+				response := testkit.NewClient(t, router).Do(request)
+
+				assert.Equal(t, strconv.FormatBool(test.allowed), response.Body)
+			})
+		}
+	}
+}
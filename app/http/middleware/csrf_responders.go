@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/samehelhawary/goravel-breeze/app/exceptions"
+	"github.com/samehelhawary/goravel-breeze/app/http/redirect"
+)
+
+// CSRFFailure identifies why a request failed CSRF verification.
+type CSRFFailure string
+
+const (
+	CSRFSessionMissing CSRFFailure = "session_missing"
+	CSRFTokenMissing   CSRFFailure = "token_missing"
+	CSRFTokenMismatch  CSRFFailure = "token_mismatch"
+	CSRFOriginRejected CSRFFailure = "origin_rejected"
+)
+
+// Message returns a short description of the failure.
+func (f CSRFFailure) Message() string {
+	switch f {
+	case CSRFSessionMissing:
+		return "Session not found"
+	case CSRFOriginRejected:
+		return "Cross-origin request rejected"
+	default:
+		return "CSRF token mismatch"
+	}
+}
+
+// CSRFResponder responds to a request that failed CSRF verification. The
+// request must not be passed on to the next handler.
+type CSRFResponder func(ctx http.Context, failure CSRFFailure)
+
+// HTMLResponder sends browsers back to the form with their input when the
+// Referer is a page of this application, and shows the "Page Expired" page
+// otherwise, so a foreign page can neither be redirected to nor have input
+// flashed for it. Requests rejected for their origin get a 403 page instead.
+// XHR and API clients receive the JSONResponder error.
+func HTMLResponder(ctx http.Context, failure CSRFFailure) {
+	if exceptions.WantsJson(ctx) {
+		JSONResponder(ctx, failure)
+		return
+	}
+
+	if failure == CSRFOriginRejected {
+		exceptions.Halt(ctx, http.StatusForbidden, failure.Message()+".")
+		return
+	}
+
+	if referer := ctx.Request().Header("Referer"); referer != "" && sameOrigin(ctx, referer) {
+		exceptions.Send(ctx, redirect.New(ctx).Back().WithInput().
+			With("status", "Your session has expired. Please try again.").Go())
+		return
+	}
+
+	exceptions.Halt(ctx, 419, "Page Expired")
+}
+
+// JSONResponder responds with a 403 and {"error": message}.
+func JSONResponder(ctx http.Context, failure CSRFFailure) {
+	ctx.Request().AbortWithStatusJson(http.StatusForbidden, http.Json{
+		"error": failure.Message(),
+	})
+}
+
+// APIResponder responds with the {"success", "message", "code"} envelope used
+// by API routes.
+func APIResponder(ctx http.Context, failure CSRFFailure) {
+	switch failure {
+	case CSRFOriginRejected:
+		ctx.Request().AbortWithStatusJson(http.StatusForbidden, http.Json{
+			"success": false,
+			"message": "Cross-origin request rejected",
+			"code":    "CSRF_ORIGIN_REJECTED",
+		})
+	case CSRFTokenMismatch:
+		ctx.Request().AbortWithStatusJson(http.StatusUnauthorized, http.Json{
+			"success": false,
+			"message": "Invalid CSRF token",
+			"code":    "CSRF_TOKEN_INVALID",
+		})
+	default:
+		ctx.Request().AbortWithStatusJson(http.StatusUnauthorized, http.Json{
+			"success": false,
+			"message": "CSRF token required",
+			"code":    "CSRF_TOKEN_MISSING",
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/goravel/framework/contracts/http"
+	"github.com/samehelhawary/goravel-breeze/testkit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTMLResponderOnlyGoesBackToThisApplication(t *testing.T) {
+	tests := []struct {
+		name     string
+		referer  string
+		redirect bool
+	}{
+		{"same origin", "http://localhost/profile", true},
+		{"explicit default port", "http://LOCALHOST:80/profile", true},
+		{"application url behind a proxy", "https://app.example.com/profile", true},
+		{"foreign origin", "https://evil.example/profile", false},
+		{"other port", "http://localhost:8080/profile", false},
+		{"other scheme", "https://localhost/profile", false},
+		{"relative", "/profile", false},
+		{"no referer", "", false},
+	}
+
+	for _, driver := range testkit.Drivers {
+		for _, test := range tests {
+			t.Run(string(driver)+"/"+test.name, func(t *testing.T) {
+				app := testkit.New(t, map[string]any{"app.url": "https://app.example.com"})
+				sessions := testkit.NewSessions(nil)
+				router := app.Route(t, driver)
+				router.Middleware(sessions.Middleware(), func(ctx http.Context) {
+					HTMLResponder(ctx, CSRFTokenMismatch)
+				}).Post("/profile", func(ctx http.Context) http.Response {
+					return ctx.Response().String(http.StatusOK, "reached")
+				})
+
+				request := testkit.NewRequest(http.MethodPost, "/profile", strings.NewReader(url.Values{"name": {"Jane"}}.Encode()))
+				request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				if test.referer != "" {
+					request.Header.Set("Referer", test.referer)
+				}
+				response := testkit.NewClient(t, router).Do(request)
+
+				if test.redirect {
+					assert.Equal(t, http.StatusFound, response.StatusCode)
+					assert.Equal(t, test.referer, response.Header.Get("Location"))
+					assert.True(t, sessions.Current().Has("_old_input"))
+				} else {
+					assert.Equal(t, 419, response.StatusCode)
+					assert.Contains(t, response.Body, "view: errors/419")
+					assert.Empty(t, response.Header.Get("Location"))
+					assert.False(t, sessions.Current().Has("_old_input"))
+				}
+			})
+		}
+	}
+}
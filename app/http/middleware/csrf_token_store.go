@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/goravel/framework/contracts/cache"
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/facades"
+)
+
+// TokenStore persists the CSRF token of the current session.
+type TokenStore interface {
+	// Get returns the stored token, or an empty string when there is none.
+	Get(ctx http.Context) string
+	// Put stores the token for the current session.
+	Put(ctx http.Context, token string) error
+	// Forget removes the stored token, so a new one is issued next request.
+	Forget(ctx http.Context) error
+}
+
+// NewTokenStore returns the store selected by breeze.csrf.store.
+func NewTokenStore() TokenStore {
+	if facades.Config().GetString("breeze.csrf.store") == "cache" {
+		return NewCacheTokenStore(facades.Cache())
+	}
+
+	return NewSessionTokenStore()
+}
+
+// SessionTokenStore keeps the token in the session, so it shares the
+// session's lifetime and is dropped along with it.
+type SessionTokenStore struct {
+	key string
+}
+
+func NewSessionTokenStore() *SessionTokenStore {
+	return &SessionTokenStore{key: "csrf_token"}
+}
+
+func (s *SessionTokenStore) Get(ctx http.Context) string {
+	token, _ := ctx.Request().Session().Get(s.key, "").(string)
+
+	return token
+}
+
+func (s *SessionTokenStore) Put(ctx http.Context, token string) error {
+	ctx.Request().Session().Put(s.key, token)
+
+	return nil
+}
+
+func (s *SessionTokenStore) Forget(ctx http.Context) error {
+	ctx.Request().Session().Forget(s.key)
+
+	return nil
+}
+
+// CacheTokenStore keeps the token in the cache under the session ID. Entries
+// expire with the configured session lifetime.
+type CacheTokenStore struct {
+	cache cache.Cache
+}
+
+func NewCacheTokenStore(cache cache.Cache) *CacheTokenStore {
+	return &CacheTokenStore{cache: cache}
+}
+
+func (s *CacheTokenStore) Get(ctx http.Context) string {
+	key := s.key(ctx)
+	if key == "" {
+		return ""
+	}
+
+	return s.cache.GetString(key)
+}
+
+func (s *CacheTokenStore) Put(ctx http.Context, token string) error {
+	key := s.key(ctx)
+	if key == "" {
+		return nil
+	}
+
+	lifetime := time.Duration(facades.Config().GetInt("session.lifetime", 120)) * time.Minute
+
+	return s.cache.Put(key, token, lifetime)
+}
+
+func (s *CacheTokenStore) Forget(ctx http.Context) error {
+	key := s.key(ctx)
+	if key == "" {
+		return nil
+	}
+
+	s.cache.Forget(key)
+
+	return nil
+}
+
+func (s *CacheTokenStore) key(ctx http.Context) string {
+	sessionID := ctx.Request().Session().GetID()
+	if sessionID == "" {
+		return ""
+	}
+
+	return "csrf_token:" + sessionID
+}
@@ -1,282 +1,513 @@
 package middleware
 
 import (
-	"crypto/aes"
-	"crypto/cipher"
 	"crypto/hmac"
-	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
 	"errors"
 	"fmt"
-	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"sync"
 
 	httpContract "github.com/goravel/framework/contracts/http"
 	"github.com/goravel/framework/facades"
+	"github.com/samehelhawary/goravel-breeze/app/exceptions"
+	"github.com/samehelhawary/goravel-breeze/contracts"
+	"github.com/samehelhawary/goravel-breeze/crypt"
+	breeze "github.com/samehelhawary/goravel-breeze/facades"
 )
 
-// --- Contracts and Implementations ---
-
-// DecryptException represents a failure during the decryption process.
-type DecryptException struct {
-	Err error
+// ValuePrefixer creates and validates the signed prefix bound to a cookie's
+// name, which stops an encrypted value from being replayed as another cookie.
+type ValuePrefixer interface {
+	Create(name string) string
+	Match(name, value string, allKeys []string) (string, bool, error)
 }
 
-func (e *DecryptException) Error() string {
-	return fmt.Sprintf("decryption failed: %v", e.Err)
+// CookieValuePrefix handles adding and validating a signed HMAC prefix to cookie values.
+type CookieValuePrefix struct {
+	key string
 }
 
-// EncrypterContract defines the interface for an encryption service.
-type EncrypterContract interface {
-	Encrypt(value string, serialize bool) (string, error)
-	Decrypt(payload string, unserialize bool) (string, error)
-	GetKey() string
-	GetAllKeys() []string
+func NewCookieValuePrefix(key string) *CookieValuePrefix {
+	return &CookieValuePrefix{key: key}
 }
 
-// AesEncrypter provides an AES-256 GCM implementation of the EncrypterContract.
-type AesEncrypter struct {
-	key []byte
+func (c *CookieValuePrefix) Create(name string) string {
+	return cookiePrefix(c.key, name) + "|"
 }
 
-// NewAesEncrypter creates a new AES encrypter. The key must be 32 bytes.
-func NewAesEncrypter(key string) (*AesEncrypter, error) {
-	if len(key) != 32 {
-		return nil, errors.New("encryption key must be 32 bytes for AES-256")
-	}
-	return &AesEncrypter{key: []byte(key)}, nil
+func cookiePrefix(key, name string) string {
+	h := hmac.New(sha256.New, []byte(key))
+	h.Write([]byte(name + "v2"))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
 }
 
-// Encrypt encrypts a string using AES-256 GCM.
-func (e *AesEncrypter) Encrypt(value string, serialize bool) (string, error) {
-	block, err := aes.NewCipher(e.key)
-	if err != nil {
-		return "", err
-	}
-	aesGCM, err := cipher.NewGCM(block)
-	if err != nil {
-		return "", err
-	}
-	nonce := make([]byte, aesGCM.NonceSize())
-	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
-		return "", err
-	}
-	ciphertext := aesGCM.Seal(nonce, nonce, []byte(value), nil)
-	return base64.StdEncoding.EncodeToString(ciphertext), nil
+func (c *CookieValuePrefix) Validate(name, value string, allKeys []string) (string, error) {
+	actualValue, _, err := c.Match(name, value, allKeys)
+	return actualValue, err
 }
 
-// Decrypt decrypts a string using AES-256 GCM.
-func (e *AesEncrypter) Decrypt(payload string, unserialize bool) (string, error) {
-	data, err := base64.StdEncoding.DecodeString(payload)
-	if err != nil {
-		return "", &DecryptException{Err: err}
-	}
-	block, err := aes.NewCipher(e.key)
-	if err != nil {
-		return "", &DecryptException{Err: err}
-	}
-	aesGCM, err := cipher.NewGCM(block)
-	if err != nil {
-		return "", &DecryptException{Err: err}
-	}
-	nonceSize := aesGCM.NonceSize()
-	if len(data) < nonceSize {
-		return "", &DecryptException{Err: errors.New("ciphertext is too short")}
+// Match validates the prefix like Validate and also reports whether it was
+// created with a previous key, i.e. any key but the first of allKeys.
+func (c *CookieValuePrefix) Match(name, value string, allKeys []string) (string, bool, error) {
+	parts := strings.SplitN(value, "|", 2)
+	if len(parts) != 2 {
+		return "", false, &crypt.DecryptException{Reason: CookieBadPrefix, Err: errors.New("invalid cookie prefix format")}
 	}
-	nonce, ciphertext := data[:nonceSize], data[nonceSize:]
-	plaintext, err := aesGCM.Open(nil, nonce, ciphertext, nil)
-	if err != nil {
-		return "", &DecryptException{Err: err}
+	prefix, actualValue := parts[0], parts[1]
+	for i, key := range allKeys {
+		if hmac.Equal([]byte(prefix), []byte(cookiePrefix(key, name))) {
+			return actualValue, i > 0, nil
+		}
 	}
-	return string(plaintext), nil
-}
-
-func (e *AesEncrypter) GetKey() string {
-	return string(e.key)
+	return "", false, &crypt.DecryptException{Reason: CookieBadPrefix, Err: errors.New("invalid cookie prefix signature")}
 }
 
-func (e *AesEncrypter) GetAllKeys() []string {
-	return []string{string(e.key)}
-}
-
-// CookieValuePrefix handles adding and validating a signed HMAC prefix to cookie values.
-type CookieValuePrefix struct {
+// CookieSigner signs cookie values without encrypting them, so they stay
+// readable (e.g. by frontend JavaScript) but cannot be tampered with. The
+// signature covers the cookie's name and value and precedes the value,
+// separated by a "|".
+type CookieSigner struct {
 	key string
 }
 
-func NewCookieValuePrefix(key string) *CookieValuePrefix {
-	return &CookieValuePrefix{key: key}
+func NewCookieSigner(key string) *CookieSigner {
+	return &CookieSigner{key: key}
 }
 
-func (c *CookieValuePrefix) Create(name string) string {
-	h := hmac.New(sha256.New, []byte(c.key))
-	h.Write([]byte(name + "v2"))
-	return base64.StdEncoding.EncodeToString(h.Sum(nil)) + "|"
+func (s *CookieSigner) Sign(name, value string) string {
+	return s.signature(name, value) + "|" + value
 }
 
-func (c *CookieValuePrefix) Validate(name, value string, allKeys []string) (string, error) {
-	parts := strings.SplitN(value, "|", 2)
-	if len(parts) != 2 {
-		return "", errors.New("invalid cookie prefix format")
+// Verify returns the value of a signed cookie, and whether it was signed with
+// a previous key, i.e. any key but the first of allKeys.
+func (s *CookieSigner) Verify(name, signed string, allKeys []string) (string, bool, error) {
+	signature, value, ok := strings.Cut(signed, "|")
+	if !ok {
+		return "", false, &crypt.DecryptException{Reason: CookieBadSignature, Err: errors.New("invalid signed cookie format")}
 	}
-	prefix, actualValue := parts[0], parts[1]
-	for _, key := range allKeys {
-		validator := NewCookieValuePrefix(key)
-		expectedPrefix := strings.TrimSuffix(validator.Create(name), "|")
-		if hmac.Equal([]byte(prefix), []byte(expectedPrefix)) {
-			return actualValue, nil
+	for i, key := range allKeys {
+		expected := NewCookieSigner(key).signature(name, value)
+		if hmac.Equal([]byte(signature), []byte(expected)) {
+			return value, i > 0, nil
 		}
 	}
-	return "", errors.New("invalid cookie prefix signature")
+	return "", false, &crypt.DecryptException{Reason: CookieBadSignature, Err: errors.New("invalid cookie signature")}
+}
+
+func (s *CookieSigner) signature(name, value string) string {
+	h := hmac.New(sha256.New, []byte(s.key))
+	h.Write([]byte(name + "v2|" + value))
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
 }
 
 // --- EncryptCookies Middleware ---
 
+// CookieMode controls how EncryptCookies protects a cookie.
+type CookieMode string
+
+const (
+	// CookieEncrypted cookies are encrypted and bound to their name. This is
+	// the default mode.
+	CookieEncrypted CookieMode = "encrypted"
+	// CookieSigned cookies stay readable but are signed against tampering.
+	CookieSigned CookieMode = "signed"
+	// CookiePlain cookies are passed through untouched.
+	CookiePlain CookieMode = "plain"
+)
+
 // EncryptCookies is middleware for encrypting and decrypting HTTP cookies.
 type EncryptCookies struct {
-	encrypter EncrypterContract
-	except    map[string]bool
+	encrypter contracts.Encrypter
+	err       error
+	modes     map[string]CookieMode
+	serialize map[string]bool
+	plaintext map[string]bool
+
+	// Derived from the encrypter once, rather than for every cookie.
+	keys   []string
+	prefix ValuePrefixer
+	signer *CookieSigner
 }
 
-var (
-	neverEncrypt sync.Map
-	serialize    bool
+var neverEncrypt sync.Map
+
+// Context keys holding the cookies of the request that were accepted in
+// plaintext by AllowPlaintext, and that were encrypted with a previous key.
+const (
+	plaintextCookiesKey = "breeze_plaintext_cookies"
+	staleCookiesKey     = "breeze_stale_cookies"
 )
 
 // NewEncryptCookies creates a new EncryptCookies middleware instance.
 // It automatically initializes the encrypter from the application's configuration.
+// Keys listed in breeze.encryption.previous_keys are still accepted when
+// decrypting, and cookies using them are re-encrypted with the current key.
+//
+// An invalid key is logged when the middleware is constructed, and requests
+// then fail with a server error instead of crashing the application.
 func NewEncryptCookies() *EncryptCookies {
-	appKey := facades.Config().GetString("APP_KEY")
-	aesEncrypter, err := NewAesEncrypter(appKey)
-	if err != nil {
-		// Panic because this is a critical configuration error.
-		panic(fmt.Sprintf("FATAL: Failed to create cookie encrypter: %v", err))
+	aesEncrypter, err := encrypterFromConfig()
+	cookies := &EncryptCookies{
+		err:       err,
+		modes:     make(map[string]CookieMode),
+		serialize: make(map[string]bool),
+		plaintext: make(map[string]bool),
+	}
+	if err == nil {
+		cookies.encrypter = aesEncrypter
+		cookies.keys = aesEncrypter.GetAllKeys()
+		cookies.signer = NewCookieSigner(aesEncrypter.GetKey())
+		if cookies.laravel() {
+			cookies.prefix = NewLaravelCookieValuePrefix(aesEncrypter.GetKey())
+		} else {
+			cookies.prefix = NewCookieValuePrefix(aesEncrypter.GetKey())
+		}
 	}
 
-	return &EncryptCookies{
-		encrypter: aesEncrypter,
-		except:    make(map[string]bool),
+	return cookies.
+		DisableFor(configStrings("breeze.cookies.plain")...).
+		SignOnly(configStrings("breeze.cookies.signed")...).
+		Serialize(configStrings("breeze.cookies.serialize")...)
+}
+
+// encrypterFromConfig resolves the shared encrypter for a new middleware, so
+// each middleware sees the key configured when it is constructed.
+func encrypterFromConfig() (contracts.Encrypter, error) {
+	encrypter, err := breeze.Breeze().Encrypter()
+	if err != nil {
+		err = fmt.Errorf("invalid cookie encryption key, run \"go run . artisan breeze:key\" to generate one: %w", err)
+		facades.Log().Error(err)
+		return nil, err
 	}
+
+	return encrypter, nil
 }
 
-// DisableFor adds cookie names to the 'except' list for this middleware instance
-// and returns the middleware instance for fluent chaining.
+// DisableFor leaves the named cookies unencrypted and returns the middleware
+// instance for fluent chaining.
 func (m *EncryptCookies) DisableFor(names ...string) *EncryptCookies {
+	return m.Mode(CookiePlain, names...)
+}
+
+// SignOnly makes the named cookies signed rather than encrypted and returns
+// the middleware instance for fluent chaining.
+func (m *EncryptCookies) SignOnly(names ...string) *EncryptCookies {
+	return m.Mode(CookieSigned, names...)
+}
+
+// Mode sets how the named cookies are protected and returns the middleware
+// instance for fluent chaining.
+func (m *EncryptCookies) Mode(mode CookieMode, names ...string) *EncryptCookies {
+	for _, name := range names {
+		m.modes[name] = mode
+	}
+	return m
+}
+
+// Serialize marks cookies whose values are PHP serialized before encryption,
+// as Laravel's encrypt() helper does, and returns the middleware instance for
+// fluent chaining. It only applies to encrypters implementing
+// contracts.PHPSerializer, i.e. the laravel format, and logs a warning with
+// any other; Go values are stored with cookies.Cookie instead.
+func (m *EncryptCookies) Serialize(names ...string) *EncryptCookies {
+	if _, ok := m.encrypter.(contracts.PHPSerializer); !ok && m.err == nil && len(names) > 0 {
+		facades.Log().Warningf("cookies %s are only PHP serialized with the laravel encryption format, they are encrypted as they are", strings.Join(names, ", "))
+	}
+	for _, name := range names {
+		m.serialize[name] = true
+	}
+	return m
+}
+
+// AllowPlaintext accepts values of the named cookies that are not encrypted,
+// such as cookies set before they were encrypted, instead of dropping them,
+// and returns the middleware instance for fluent chaining. IsPlaintextCookie
+// reports them, so the application can queue them again to have them
+// encrypted.
+func (m *EncryptCookies) AllowPlaintext(names ...string) *EncryptCookies {
 	for _, name := range names {
-		m.except[name] = true
+		m.plaintext[name] = true
 	}
 	return m
 }
 
+// IsPlaintextCookie reports whether the named cookie of the request was
+// accepted in plaintext by AllowPlaintext.
+func IsPlaintextCookie(ctx httpContract.Context, name string) bool {
+	return cookieMarked(ctx, plaintextCookiesKey, name)
+}
+
+// IsStaleCookie reports whether the named cookie of the request was encrypted
+// with a previous key. Such cookies are sent again with the current key as
+// browser session cookies, since their lifetime is unknown; the application
+// can queue them again with their own lifetime instead.
+func IsStaleCookie(ctx httpContract.Context, name string) bool {
+	return cookieMarked(ctx, staleCookiesKey, name)
+}
+
 // Handle processes the HTTP request and response.
 func (m *EncryptCookies) Handle() httpContract.Middleware {
 	return func(ctx httpContract.Context) {
-		m.decryptRequestCookies(ctx.Request().Origin())
+		if m.err != nil {
+			exceptions.Send(ctx, exceptions.Render(ctx, httpContract.StatusInternalServerError, m.err))
+			return
+		}
+		stale := m.decryptRequestCookies(ctx)
+		encrypt := beforeHeadersWritten(ctx, func() {
+			m.encryptResponseCookies(ctx, stale)
+		})
 		ctx.Request().Next()
-		m.encryptResponseCookies(ctx)
+		encrypt()
 	}
 }
 
-func (m *EncryptCookies) decryptRequestCookies(r *http.Request) {
-	if len(r.Cookies()) == 0 {
-		return
+// decryptRequestCookies replaces the request's cookies with their decrypted
+// values, keeping their order. Cookies that fail to decrypt are dropped. It
+// returns the cookies that were encrypted with a previous key.
+func (m *EncryptCookies) decryptRequestCookies(ctx httpContract.Context) map[string]string {
+	cookies := requestCookies(ctx)
+	if len(cookies) == 0 {
+		return nil
 	}
-	decryptedCookies := make(map[string]string)
-	for _, cookie := range r.Cookies() {
+	var stale map[string]string
+	decrypted := cookies[:0]
+	changed := false
+	for _, cookie := range cookies {
 		if m.isDisabled(cookie.Name) {
-			decryptedCookies[cookie.Name] = cookie.Value
+			decrypted = append(decrypted, cookie)
 			continue
 		}
-		decryptedValue, err := m.decryptCookie(cookie.Name, cookie.Value)
-		if err != nil {
-			fmt.Printf("Warning: could not decrypt cookie '%s': %v\n", cookie.Name, err)
+		changed = true
+		validatedValue, isStale, err := m.decrypt(cookie.Name, cookie.Value)
+		if err != nil && m.acceptPlaintext(ctx, cookie.Name) {
+			decrypted = append(decrypted, cookie)
 			continue
 		}
-		prefixer := NewCookieValuePrefix(m.encrypter.GetKey())
-		validatedValue, err := prefixer.Validate(cookie.Name, decryptedValue, m.encrypter.GetAllKeys())
 		if err != nil {
-			fmt.Printf("Warning: invalid prefix for cookie '%s': %v\n", cookie.Name, err)
+			reportCookieFailure(ctx, cookie.Name, failureReason(err), err)
 			continue
 		}
-		decryptedCookies[cookie.Name] = validatedValue
+		if isStale {
+			if stale == nil {
+				stale = make(map[string]string)
+			}
+			stale[cookie.Name] = validatedValue
+			markCookie(ctx, staleCookiesKey, cookie.Name)
+		}
+		cookie.Value = validatedValue
+		if m.mode(cookie.Name) == CookieEncrypted {
+			cookie.Value = encodeRequestCookie(ctx, validatedValue)
+		}
+		decrypted = append(decrypted, cookie)
+	}
+	if changed {
+		replaceRequestCookies(ctx, decrypted)
 	}
-	r.Header.Del("Cookie")
-	var newCookieStrings []string
-	for name, value := range decryptedCookies {
-		newCookieStrings = append(newCookieStrings, fmt.Sprintf("%s=%s", name, value))
+	return stale
+}
+
+// acceptPlaintext records that the named cookie, which failed to decrypt, is
+// accepted in plaintext, when AllowPlaintext allows it.
+func (m *EncryptCookies) acceptPlaintext(ctx httpContract.Context, name string) bool {
+	if !m.plaintext[name] || m.mode(name) != CookieEncrypted {
+		return false
 	}
-	if len(newCookieStrings) > 0 {
-		r.Header.Set("Cookie", strings.Join(newCookieStrings, "; "))
+	markCookie(ctx, plaintextCookiesKey, name)
+	return true
+}
+
+// markCookie adds the named cookie to the set held by the context key.
+func markCookie(ctx httpContract.Context, key, name string) {
+	marked, ok := ctx.Value(key).(map[string]bool)
+	if !ok {
+		marked = map[string]bool{}
+		ctx.WithValue(key, marked)
 	}
+	marked[name] = true
+}
+
+func cookieMarked(ctx httpContract.Context, key, name string) bool {
+	marked, _ := ctx.Value(key).(map[string]bool)
+	return marked[name]
+}
+
+// Decrypt decrypts a value encrypted for the named cookie and validates its
+// prefix. It is also used for cookie values echoed back in headers, such as
+// the XSRF-TOKEN cookie sent as X-XSRF-TOKEN.
+func (m *EncryptCookies) Decrypt(name, value string) (string, error) {
+	decryptedValue, _, err := m.decrypt(name, value)
+	return decryptedValue, err
+}
+
+// decrypt is Decrypt, also reporting whether the value was encrypted with a
+// previous key.
+func (m *EncryptCookies) decrypt(name, value string) (string, bool, error) {
+	if m.err != nil {
+		return "", false, m.err
+	}
+	if m.mode(name) == CookieSigned {
+		return m.signer.Verify(name, value, m.keys)
+	}
+	if m.laravel() && strings.Contains(value, "%") {
+		if unescaped, err := url.PathUnescape(value); err == nil {
+			value = unescaped
+		}
+	}
+	decryptedValue, err := m.decryptCookie(name, value)
+	if err != nil {
+		return "", false, err
+	}
+	return m.prefix.Match(name, decryptedValue, m.keys)
 }
 
 func (m *EncryptCookies) decryptCookie(name, value string) (string, error) {
-	return m.encrypter.Decrypt(value, m.serialized(name))
+	if serializer, ok := m.serializer(name); ok {
+		return serializer.DecryptSerialized(value)
+	}
+	return m.encrypter.DecryptString(value)
 }
 
-func (m *EncryptCookies) encryptResponseCookies(ctx httpContract.Context) {
-	responseHeaders := ctx.Response().Origin().Header()
-	setCookieHeaders := append([]string{}, responseHeaders["Set-Cookie"]...)
-	if len(setCookieHeaders) == 0 {
+func (m *EncryptCookies) encryptCookie(name, value string) (string, error) {
+	if serializer, ok := m.serializer(name); ok {
+		return serializer.EncryptSerialized(value)
+	}
+	return m.encrypter.EncryptString(value)
+}
+
+func (m *EncryptCookies) encryptResponseCookies(ctx httpContract.Context, stale map[string]string) {
+	headers := responseSetCookies(ctx)
+	if len(headers) == 0 && len(stale) == 0 {
 		return
 	}
-	responseHeaders.Del("Set-Cookie")
-	for _, cookieStr := range setCookieHeaders {
-		m.handleOutgoingCookie(ctx, cookieStr)
+	outgoing := make([]string, 0, len(headers)+len(stale))
+	for _, raw := range headers {
+		cookie, err := http.ParseSetCookie(raw)
+		if err != nil {
+			outgoing = append(outgoing, raw)
+			continue
+		}
+		delete(stale, cookie.Name)
+		if m.mode(cookie.Name) == CookieEncrypted {
+			cookie.Value = decodeResponseCookie(ctx, cookie.Value)
+		}
+		outgoing = append(outgoing, m.handleOutgoingCookie(ctx, cookie, raw))
 	}
+	outgoing = append(outgoing, m.reencryptStaleCookies(ctx, stale)...)
+	replaceResponseSetCookies(ctx, mergeSetCookies(outgoing))
 }
 
-func (m *EncryptCookies) handleOutgoingCookie(ctx httpContract.Context, cookieStr string) {
-	dummyRes := http.Response{Header: http.Header{"Set-Cookie": {cookieStr}}}
-	parsedCookies := dummyRes.Cookies()
-	if len(parsedCookies) == 0 {
-		return
+// reencryptStaleCookies returns cookies that were encrypted with a previous
+// key, encrypted with the current one, unless the response already set them.
+// The original attributes are not sent by browsers, so the session cookie
+// settings are used, without a lifetime: a guessed one could cut a long lived
+// cookie short, see IsStaleCookie.
+func (m *EncryptCookies) reencryptStaleCookies(ctx httpContract.Context, stale map[string]string) []string {
+	headers := make([]string, 0, len(stale))
+	for name, value := range stale {
+		cookie := &http.Cookie{
+			Name:     name,
+			Value:    value,
+			Path:     facades.Config().GetString("session.path", "/"),
+			Domain:   facades.Config().GetString("session.domain"),
+			Secure:   facades.Config().GetBool("session.secure"),
+			HttpOnly: facades.Config().GetBool("session.http_only", true),
+			SameSite: sameSiteMode(facades.Config().GetString("session.same_site", "lax")),
+		}
+		headers = append(headers, m.handleOutgoingCookie(ctx, cookie, cookie.String()))
 	}
-	cookie := parsedCookies[0]
-	if cookie.MaxAge < 0 {
-		ctx.Response().Origin().Header().Add("Set-Cookie", cookieStr)
-		return
+	return headers
+}
+
+// handleOutgoingCookie returns the Set-Cookie header for a cookie set by the
+// application, with its value encrypted or signed and every other attribute
+// kept as is.
+func (m *EncryptCookies) handleOutgoingCookie(ctx httpContract.Context, cookie *http.Cookie, raw string) string {
+	if cookie.MaxAge < 0 || m.isDisabled(cookie.Name) {
+		return raw
 	}
-	if m.isDisabled(cookie.Name) {
-		ctx.Response().Cookie(toContractCookie(cookie))
-		return
+	if m.mode(cookie.Name) == CookieSigned {
+		cookie.Value = m.signer.Sign(cookie.Name, cookie.Value)
+		return formatSetCookie(cookie)
 	}
-	prefixer := NewCookieValuePrefix(m.encrypter.GetKey())
-	valueToEncrypt := prefixer.Create(cookie.Name) + cookie.Value
-	encryptedValue, err := m.encrypter.Encrypt(valueToEncrypt, m.serialized(cookie.Name))
+	valueToEncrypt := m.prefix.Create(cookie.Name) + cookie.Value
+	encryptedValue, err := m.encryptCookie(cookie.Name, valueToEncrypt)
 	if err != nil {
-		fmt.Printf("Warning: could not encrypt cookie '%s': %v\n", cookie.Name, err)
-		return
+		reportCookieFailure(ctx, cookie.Name, CookieEncryptFailed, err)
+		// Never send the plaintext in place of the encrypted value
+		cookie.Value = ""
+		cookie.MaxAge = -1
+		return formatSetCookie(cookie)
+	}
+	// PHP URL-decodes cookies, which would turn "+" into a space
+	if m.laravel() {
+		encryptedValue = url.QueryEscape(encryptedValue)
 	}
-	encryptedCookie := toContractCookie(cookie)
-	encryptedCookie.Value = encryptedValue
-	ctx.Response().Cookie(encryptedCookie)
+	cookie.Value = encryptedValue
+	return formatSetCookie(cookie)
+}
+
+func (m *EncryptCookies) laravel() bool {
+	_, ok := m.encrypter.(*crypt.LaravelEncrypter)
+	return ok
 }
 
 func (m *EncryptCookies) isDisabled(name string) bool {
-	if _, ok := m.except[name]; ok {
+	if m.mode(name) == CookiePlain {
 		return true
 	}
 	_, ok := neverEncrypt.Load(name)
 	return ok
 }
 
-func (m *EncryptCookies) serialized(name string) bool {
-	return serialize
+func (m *EncryptCookies) mode(name string) CookieMode {
+	if mode, ok := m.modes[name]; ok {
+		return mode
+	}
+	return CookieEncrypted
+}
+
+// serializer returns the encrypter's PHP serializer for cookies marked with
+// Serialize.
+func (m *EncryptCookies) serializer(name string) (contracts.PHPSerializer, bool) {
+	if !m.serialize[name] {
+		return nil, false
+	}
+	serializer, ok := m.encrypter.(contracts.PHPSerializer)
+	return serializer, ok
+}
+
+// configStrings reads a list of strings from config, given either as a slice
+// or as a comma separated string.
+func configStrings(key string) []string {
+	switch values := facades.Config().Get(key).(type) {
+	case []string:
+		return values
+	case string:
+		var result []string
+		for _, value := range strings.Split(values, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				result = append(result, value)
+			}
+		}
+		return result
+	default:
+		return nil
+	}
 }
 
-func toContractCookie(c *http.Cookie) httpContract.Cookie {
-	return httpContract.Cookie{
-		Name:     c.Name,
-		Value:    c.Value,
-		Path:     c.Path,
-		Domain:   c.Domain,
-		Expires:  c.Expires,
-		MaxAge:   c.MaxAge,
-		Secure:   c.Secure,
-		HttpOnly: c.HttpOnly,
+func sameSiteMode(sameSite string) http.SameSite {
+	switch strings.ToLower(sameSite) {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	case "lax":
+		return http.SameSiteLaxMode
+	default:
+		return http.SameSiteDefaultMode
 	}
 }
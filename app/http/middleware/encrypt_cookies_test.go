@@ -0,0 +1,317 @@
+package middleware
+
+import (
+	"fmt"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	gingonic "github.com/gin-gonic/gin"
+	fiberv2 "github.com/gofiber/fiber/v2"
+	"github.com/goravel/fiber"
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/contracts/log"
+	"github.com/goravel/gin"
+	"github.com/samehelhawary/goravel-breeze/app/http/cookies"
+	"github.com/samehelhawary/goravel-breeze/testkit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestEncryptCookiesEncryptTheValueTheApplicationSet(t *testing.T) {
+	const value = "dark+blue=1/2 & 100%"
+
+	for _, driver := range testkit.Drivers {
+		t.Run(string(driver), func(t *testing.T) {
+			router := newApp(t, nil).Route(t, driver)
+			cookies := NewEncryptCookies()
+			router.Middleware(cookies.Handle()).Get("/set", func(ctx http.Context) http.Response {
+				return ctx.Response().Cookie(http.Cookie{Name: "theme", Value: value, Path: "/"}).String(http.StatusOK, "set")
+			})
+			router.Middleware(cookies.Handle()).Get("/get", func(ctx http.Context) http.Response {
+				return ctx.Response().String(http.StatusOK, ctx.Request().Cookie("theme"))
+			})
+
+			client := testkit.NewClient(t, router)
+			client.Get("/set")
+
+			decrypted, err := cookies.Decrypt("theme", client.Cookie("theme"))
+			assert.NoError(t, err)
+			assert.Equal(t, value, decrypted)
+			assert.Equal(t, value, client.Get("/get").Body)
+		})
+	}
+}
+
+func TestEncryptCookiesResolveTheKeyPerMiddleware(t *testing.T) {
+	invalid := newApp(t, map[string]any{"breeze.encryption.key": "too-short"})
+	broken := NewEncryptCookies()
+	router := invalid.Route(t, testkit.Gin)
+	router.Middleware(broken.Handle()).Get("/", func(ctx http.Context) http.Response {
+		return ctx.Response().String(http.StatusOK, "reached")
+	})
+	assert.Equal(t, http.StatusInternalServerError, testkit.NewClient(t, router).Get("/").StatusCode)
+	assert.NotEmpty(t, invalid.Logs().Entries())
+
+	newApp(t, nil)
+	current := NewEncryptCookies()
+	payload, err := current.encrypter.EncryptString("value")
+	assert.NoError(t, err)
+
+	newApp(t, map[string]any{"breeze.encryption.key": "another-key-that-is-32-bytes-ok!"})
+	rotated := NewEncryptCookies()
+	_, err = rotated.encrypter.DecryptString(payload)
+	assert.Error(t, err)
+	assert.NotEqual(t, current.encrypter.GetKey(), rotated.encrypter.GetKey())
+}
+
+func TestEncryptCookiesSerializeTypedCookiesOnce(t *testing.T) {
+	type settings struct {
+		Theme string `json:"theme"`
+	}
+
+	const encoded = `%7B%22theme%22:%22dark%22%7D`
+	tests := []struct {
+		format    string
+		plaintext func(value string) string
+	}{
+		// The JSON of cookies.Cookie is encrypted as it is
+		{"breeze", func(value string) string { return value }},
+		// and only the laravel format PHP serializes it, for Laravel's decrypt()
+		{"laravel", func(value string) string { return fmt.Sprintf(`s:%d:"%s";`, len(value), value) }},
+	}
+
+	for _, driver := range testkit.Drivers {
+		for _, test := range tests {
+			t.Run(string(driver)+"/"+test.format, func(t *testing.T) {
+				app := newApp(t, map[string]any{
+					"breeze.encryption.format": test.format,
+					"breeze.cookies.serialize": []string{"settings"},
+				})
+				router := app.Route(t, driver)
+				typed := cookies.New[settings]("settings", 60)
+				encrypt := NewEncryptCookies()
+				// The serialize list does nothing with other formats, which is reported
+				assert.Equal(t, test.format != "laravel", app.Logs().Contains(log.WarningLevel, "settings"))
+				router.Middleware(encrypt.Handle()).Get("/set", func(ctx http.Context) http.Response {
+					assert.NoError(t, typed.Set(ctx, settings{Theme: "dark"}))
+					return ctx.Response().String(http.StatusOK, "set")
+				})
+				router.Middleware(encrypt.Handle()).Get("/get", func(ctx http.Context) http.Response {
+					return ctx.Response().String(http.StatusOK, typed.GetOr(ctx, settings{Theme: "light"}).Theme)
+				})
+
+				client := testkit.NewClient(t, router)
+				client.Get("/set")
+				assert.Equal(t, "dark", client.Get("/get").Body)
+
+				// The laravel format URL-encodes the payload for PHP
+				payload, err := url.PathUnescape(client.Cookie("settings"))
+				require.NoError(t, err)
+				plaintext, err := encrypt.encrypter.DecryptString(payload)
+				require.NoError(t, err)
+				assert.Equal(t, test.plaintext(encrypt.prefix.Create("settings")+encoded), plaintext)
+			})
+		}
+	}
+}
+
+func TestEncryptCookiesSignCookies(t *testing.T) {
+	for _, driver := range testkit.Drivers {
+		t.Run(string(driver), func(t *testing.T) {
+			router := newApp(t, map[string]any{"breeze.cookies.signed": []string{"theme"}}).Route(t, driver)
+			signing := NewEncryptCookies()
+			router.Middleware(signing.Handle()).Get("/set", func(ctx http.Context) http.Response {
+				return ctx.Response().Cookie(http.Cookie{Name: "theme", Value: "dark", Path: "/"}).String(http.StatusOK, "set")
+			})
+			router.Middleware(signing.Handle()).Get("/get", func(ctx http.Context) http.Response {
+				return ctx.Response().String(http.StatusOK, ctx.Request().Cookie("theme"))
+			})
+
+			client := testkit.NewClient(t, router)
+			client.Get("/set")
+
+			// The value stays readable, behind its signature
+			signed := client.Cookie("theme")
+			signature, value, ok := strings.Cut(signed, "|")
+			require.True(t, ok, signed)
+			assert.Equal(t, "dark", value)
+			assert.Equal(t, "dark", client.Get("/get").Body)
+
+			failures := CookieFailureCounts()[CookieBadSignature]
+			client.SetCookie(&stdhttp.Cookie{Name: "theme", Value: signature + "|light", Path: "/"})
+			assert.Empty(t, client.Get("/get").Body, "a tampered value must be dropped")
+			assert.Equal(t, failures+1, CookieFailureCounts()[CookieBadSignature])
+		})
+	}
+}
+
+func TestEncryptCookiesAcceptAllowedPlaintextOnce(t *testing.T) {
+	const token = "legacy-remember-token"
+
+	for _, driver := range testkit.Drivers {
+		t.Run(string(driver), func(t *testing.T) {
+			router := newApp(t, nil).Route(t, driver)
+			allowing := NewEncryptCookies().AllowPlaintext("remember_me_token")
+			remember := func(ctx http.Context) http.Response {
+				value := ctx.Request().Cookie("remember_me_token")
+				plaintext := IsPlaintextCookie(ctx, "remember_me_token")
+				if plaintext {
+					cookies.Queue(ctx, "remember_me_token", value, 60)
+				}
+				return ctx.Response().String(http.StatusOK, fmt.Sprintf("%s %t", value, plaintext))
+			}
+			router.Middleware(allowing.Handle(), AddQueuedCookies()).Get("/", remember)
+			router.Middleware(NewEncryptCookies().Handle(), AddQueuedCookies()).Get("/strict", remember)
+
+			client := testkit.NewClient(t, router)
+			client.SetCookie(&stdhttp.Cookie{Name: "remember_me_token", Value: token})
+			assert.Equal(t, " false", client.Get("/strict").Body, "plaintext is dropped unless allowed")
+			assert.Equal(t, token+" true", client.Get("/").Body)
+
+			encrypted := client.Cookie("remember_me_token")
+			assert.NotEqual(t, token, encrypted, "the cookie must be queued again encrypted")
+			decrypted, err := allowing.Decrypt("remember_me_token", encrypted)
+			assert.NoError(t, err)
+			assert.Equal(t, token, decrypted)
+			assert.Equal(t, token+" false", client.Get("/").Body)
+		})
+	}
+}
+
+func TestEncryptCookiesReissueCookiesOfPreviousKeys(t *testing.T) {
+	const previousKey = "breeze-previous-key-of-32-bytes!"
+
+	for _, driver := range testkit.Drivers {
+		t.Run(string(driver), func(t *testing.T) {
+			newApp(t, map[string]any{"breeze.encryption.key": previousKey})
+			previous := NewEncryptCookies()
+			theme, err := previous.encryptCookie("theme", previous.prefix.Create("theme")+"dark")
+			require.NoError(t, err)
+			remember, err := previous.encryptCookie("remember_me_token", previous.prefix.Create("remember_me_token")+"token")
+			require.NoError(t, err)
+
+			router := newApp(t, map[string]any{"breeze.encryption.previous_keys": previousKey}).Route(t, driver)
+			current := NewEncryptCookies()
+			router.Middleware(current.Handle(), AddQueuedCookies()).Get("/", func(ctx http.Context) http.Response {
+				// The application knows the lifetime of its remember cookie
+				if IsStaleCookie(ctx, "remember_me_token") {
+					cookies.Queue(ctx, "remember_me_token", ctx.Request().Cookie("remember_me_token"), 60)
+				}
+				return ctx.Response().String(http.StatusOK, fmt.Sprintf("%s %t", ctx.Request().Cookie("theme"), IsStaleCookie(ctx, "theme")))
+			})
+
+			request := testkit.NewRequest(http.MethodGet, "/", nil)
+			request.AddCookie(&stdhttp.Cookie{Name: "theme", Value: theme})
+			request.AddCookie(&stdhttp.Cookie{Name: "remember_me_token", Value: remember})
+			response := testkit.NewClient(t, router).Do(request)
+			assert.Equal(t, "dark true", response.Body)
+
+			reissued := map[string]*stdhttp.Cookie{}
+			for _, raw := range response.Header.Values("Set-Cookie") {
+				cookie, err := stdhttp.ParseSetCookie(raw)
+				require.NoError(t, err)
+				reissued[cookie.Name] = cookie
+			}
+			require.Len(t, reissued, 2)
+
+			for name, value := range map[string]string{"theme": "dark", "remember_me_token": "token"} {
+				_, stale, err := current.decrypt(name, reissued[name].Value)
+				assert.NoError(t, err, name)
+				assert.False(t, stale, "%s must be encrypted with the current key", name)
+				decrypted, _ := current.Decrypt(name, reissued[name].Value)
+				assert.Equal(t, value, decrypted)
+			}
+
+			// Its lifetime is unknown, so the theme lives as long as the browser
+			assert.Zero(t, reissued["theme"].MaxAge)
+			assert.True(t, reissued["theme"].Expires.IsZero())
+			assert.True(t, reissued["theme"].HttpOnly)
+			assert.Equal(t, "/", reissued["theme"].Path)
+			assert.Equal(t, 3600, reissued["remember_me_token"].MaxAge)
+		})
+	}
+}
+
+// BenchmarkEncryptCookies measures decrypting the request cookies and
+// encrypting the response cookies of one request, on both drivers. Zero
+// cookies is the early return of requests and responses without any. Every
+// iteration restores the headers the previous one rewrote.
+func BenchmarkEncryptCookies(b *testing.B) {
+	newApp(b, nil)
+	middleware := NewEncryptCookies()
+
+	for _, count := range []int{0, 1, 20, 100} {
+		var (
+			requestCookies  []string
+			responseCookies []string
+		)
+		for i := range count {
+			name := fmt.Sprintf("cookie_%d", i)
+			encrypted, err := middleware.encryptCookie(name, middleware.prefix.Create(name)+"value")
+			require.NoError(b, err)
+			requestCookies = append(requestCookies, name+"="+encrypted)
+			responseCookies = append(responseCookies, name+"=value; Path=/; HttpOnly; SameSite=Lax")
+		}
+		cookieHeader := strings.Join(requestCookies, "; ")
+
+		b.Run(fmt.Sprintf("gin/decrypt/%d", count), func(b *testing.B) {
+			ctx, _ := gingonic.CreateTestContext(httptest.NewRecorder())
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+			goravelCtx := gin.NewContext(ctx)
+
+			b.ReportAllocs()
+			for b.Loop() {
+				ctx.Request.Header.Set("Cookie", cookieHeader)
+				middleware.decryptRequestCookies(goravelCtx)
+			}
+		})
+
+		b.Run(fmt.Sprintf("gin/encrypt/%d", count), func(b *testing.B) {
+			ctx, _ := gingonic.CreateTestContext(httptest.NewRecorder())
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+			goravelCtx := gin.NewContext(ctx)
+
+			b.ReportAllocs()
+			for b.Loop() {
+				if count > 0 {
+					ctx.Writer.Header()["Set-Cookie"] = append([]string(nil), responseCookies...)
+				}
+				middleware.encryptResponseCookies(goravelCtx, nil)
+			}
+		})
+
+		b.Run(fmt.Sprintf("fiber/decrypt/%d", count), func(b *testing.B) {
+			app := fiberv2.New()
+			ctx := app.AcquireCtx(&fasthttp.RequestCtx{})
+			defer app.ReleaseCtx(ctx)
+			goravelCtx := fiber.NewContext(ctx)
+
+			b.ReportAllocs()
+			for b.Loop() {
+				ctx.Request().Header.Set("Cookie", cookieHeader)
+				middleware.decryptRequestCookies(goravelCtx)
+			}
+		})
+
+		b.Run(fmt.Sprintf("fiber/encrypt/%d", count), func(b *testing.B) {
+			app := fiberv2.New()
+			ctx := app.AcquireCtx(&fasthttp.RequestCtx{})
+			defer app.ReleaseCtx(ctx)
+			goravelCtx := fiber.NewContext(ctx)
+
+			b.ReportAllocs()
+			for b.Loop() {
+				header := &ctx.Response().Header
+				header.DelAllCookies()
+				for _, raw := range responseCookies {
+					header.Add(fasthttp.HeaderSetCookie, raw)
+				}
+				middleware.encryptResponseCookies(goravelCtx, nil)
+			}
+		})
+	}
+}
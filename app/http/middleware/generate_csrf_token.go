@@ -4,56 +4,28 @@ package middleware
 import (
 	"crypto/rand"
 	"encoding/base64"
-	"github.com/goravel/framework/contracts/cache"
 	"github.com/goravel/framework/contracts/http"
 	"github.com/goravel/framework/facades"
 	"time"
 )
 
+// XSRFCookie is the readable cookie holding the CSRF token, which Axios and
+// Angular send back in the X-XSRF-TOKEN header.
+const XSRFCookie = "XSRF-TOKEN"
+
 type CSRFManager struct {
-	cache cache.Cache
+	store   TokenStore
+	cookies *EncryptCookies
+	except  []string
+	origins originPolicy
 }
 
 func NewCSRFManager() *CSRFManager {
 	return &CSRFManager{
-		cache: facades.Cache(),
-	}
-}
-
-// CSRF middleware that validates tokens
-func CSRF() http.Middleware {
-	manager := NewCSRFManager()
-
-	return func(ctx http.Context) {
-		// Skip CSRF for GET, HEAD, OPTIONS requests
-		method := ctx.Request().Method()
-		if method == "GET" || method == "HEAD" || method == "OPTIONS" {
-			ctx.Request().Next()
-			return
-		}
-
-		// Get token from request
-		token := manager.getTokenFromRequest(ctx)
-
-		// Get session ID
-		sessionID := ctx.Request().Session().GetID()
-		if sessionID == "" {
-			ctx.Request().AbortWithStatusJson(http.StatusForbidden, http.Json{
-				"error": "Session not found",
-			})
-			return
-		}
-
-		// Validate token
-		storedToken := manager.cache.Get(manager.getCacheKey(sessionID))
-		if storedToken == nil || storedToken.(string) != token {
-			ctx.Request().AbortWithStatusJson(http.StatusForbidden, http.Json{
-				"error": "CSRF token mismatch",
-			})
-			return
-		}
-
-		ctx.Request().Next()
+		store:   NewTokenStore(),
+		cookies: NewEncryptCookies(),
+		except:  csrfExclusions(),
+		origins: newOriginPolicy(),
 	}
 }
 
@@ -70,49 +42,42 @@ func GenerateCSRFToken() http.Middleware {
 		// Generate or retrieve token
 		token := manager.Token(ctx)
 
-		// Store token in session for views
-		ctx.Request().Session().Put("csrf_token", token)
-
-		// Also add to shared data for views
-		facades.View().Share("csrf_token", token)
+		// Keep the token on the request for InjectCSRFToViews
+		ctx.WithValue("csrf_token", token)
+
+		// Expose the token to JavaScript clients, encrypted by EncryptCookies
+		if token != "" {
+			ctx.Response().Cookie(http.Cookie{
+				Name:     XSRFCookie,
+				Value:    token,
+				Path:     "/",
+				MaxAge:   facades.Config().GetInt("session.lifetime", 120) * 60,
+				Secure:   facades.Config().GetBool("session.secure"),
+				HttpOnly: false,
+				SameSite: "lax",
+			})
+		}
 
 		ctx.Request().Next()
 	}
 }
 
 // Helper methods for CSRFManager
-func (c *CSRFManager) GenerateToken(sessionID string) (string, error) {
+func (c *CSRFManager) GenerateToken(ctx http.Context) (string, error) {
 	token := c.generateRandomToken()
 
-	// Store in cache for 24 hours
-	err := c.cache.Put(c.getCacheKey(sessionID), token, 24*time.Hour)
-	if err != nil {
+	if err := c.store.Put(ctx, token); err != nil {
 		return "", err
 	}
+	ctx.Request().Session().Put("csrf_token_refreshed_at", time.Now().Unix())
 
 	return token, nil
 }
 
-func (c *CSRFManager) getTokenFromRequest(ctx http.Context) string {
-	// Check header first
-	token := ctx.Request().Header("X-CSRF-TOKEN")
-	if token != "" {
-		return token
-	}
-
-	// Check form data
-	token = ctx.Request().Input("_token")
-	if token != "" {
-		return token
-	}
-
-	// Check X-XSRF-TOKEN header (common in SPAs)
-	token = ctx.Request().Header("X-XSRF-TOKEN")
-	if token != "" {
-		return token
-	}
-
-	return ""
+// Forget discards the session's token, e.g. on logout, so the next request
+// is issued a new one.
+func (c *CSRFManager) Forget(ctx http.Context) error {
+	return c.store.Forget(ctx)
 }
 
 func (c *CSRFManager) generateRandomToken() string {
@@ -121,27 +86,48 @@ func (c *CSRFManager) generateRandomToken() string {
 	return base64.URLEncoding.EncodeToString(b)
 }
 
-func (c *CSRFManager) getCacheKey(sessionID string) string {
-	return "csrf_token:" + sessionID
-}
-
 func (c *CSRFManager) Token(ctx http.Context) string {
-	sessionID := ctx.Request().Session().GetID()
-	if sessionID == "" {
+	if ctx.Request().Session().GetID() == "" {
 		return ""
 	}
 
-	// Check if token already exists in cache
-	cachedToken := c.cache.Get(c.getCacheKey(sessionID))
-	if cachedToken != nil {
-		return cachedToken.(string)
+	// Check if token already exists in the store
+	if token := c.store.Get(ctx); token != "" {
+		c.refresh(ctx, token)
+		return token
 	}
 
 	// Generate new token
-	token, err := c.GenerateToken(sessionID)
+	token, err := c.GenerateToken(ctx)
 	if err != nil {
 		return ""
 	}
 
 	return token
 }
+
+// refresh stores the token again once half of its lifetime has passed, so a
+// token kept in the cache lives as long as an active session does. The token
+// itself is kept, leaving forms in other tabs valid.
+func (c *CSRFManager) refresh(ctx http.Context, token string) {
+	session := ctx.Request().Session()
+
+	var refreshedAt int64
+	switch value := session.Get("csrf_token_refreshed_at").(type) {
+	case int64:
+		refreshedAt = value
+	case float64:
+		refreshedAt = int64(value)
+	}
+
+	lifetime := time.Duration(facades.Config().GetInt("session.lifetime", 120)) * time.Minute
+	if time.Since(time.Unix(refreshedAt, 0)) < lifetime/2 {
+		return
+	}
+
+	if err := c.store.Put(ctx, token); err != nil {
+		facades.Log().Error("failed to refresh CSRF token: ", err)
+		return
+	}
+	session.Put("csrf_token_refreshed_at", time.Now().Unix())
+}
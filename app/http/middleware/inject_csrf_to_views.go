@@ -1,27 +1,60 @@
 package middleware
 
 import (
+	"strconv"
+
 	"github.com/goravel/framework/contracts/http"
 	"github.com/goravel/framework/facades"
+	"github.com/samehelhawary/goravel-breeze/app/http/views"
 )
 
+// InjectCSRFToViews shares csrf_token, csrf_field() and csrf_meta() with the
+// views rendered for this request through views.Make. They are kept on the
+// request rather than shared with facades.View(), which would hand one
+// visitor's token to the pages of another.
 func InjectCSRFToViews() http.Middleware {
 	return func(ctx http.Context) {
-		// Get CSRF token from session
-		token := ctx.Request().Session().Get("csrf_token", "")
+		// Get the CSRF token set by GenerateCSRFToken
+		token, _ := ctx.Value("csrf_token").(string)
 
-		// Share with all views
-		facades.View().Share("csrf_token", token)
+		views.Share(ctx, "csrf_token", token)
 
-		// Also add helper functions
-		facades.View().Share("csrf_field", func() string {
-			return `<input type="hidden" name="_token" value="` + token.(string) + `">`
+		// Also add helper functions, masking the token on every render
+		views.Share(ctx, "csrf_field", func() string {
+			return `<input type="hidden" name="_token" value="` + MaskToken(token) + `">`
 		})
 
-		facades.View().Share("csrf_meta", func() string {
-			return `<meta name="csrf-token" content="` + token.(string) + `">`
+		views.Share(ctx, "csrf_meta", func() string {
+			meta := `<meta name="csrf-token" content="` + MaskToken(token) + `">`
+			if facades.Config().GetBool("breeze.csrf.refresh_script") {
+				meta += csrfRefreshScript()
+			}
+			return meta
 		})
 
 		ctx.Request().Next()
 	}
 }
+
+// csrfRefreshScript keeps the csrf-token meta tag and the _token fields of a
+// long-lived tab current by fetching /csrf-token at half the session lifetime,
+// and when the tab becomes visible again after that long.
+func csrfRefreshScript() string {
+	interval := facades.Config().GetInt("session.lifetime", 120) * 30 * 1000
+
+	return `<script>(() => {
+	let last = Date.now();
+	const refresh = async () => {
+		last = Date.now();
+		const response = await fetch('/csrf-token', {headers: {'Accept': 'application/json'}, credentials: 'same-origin'});
+		if (!response.ok) return;
+		const {token} = await response.json();
+		document.querySelector('meta[name="csrf-token"]')?.setAttribute('content', token);
+		document.querySelectorAll('input[name="_token"]').forEach((input) => input.value = token);
+	};
+	setInterval(refresh, ` + strconv.Itoa(interval) + `);
+	document.addEventListener('visibilitychange', () => {
+		if (document.visibilityState === 'visible' && Date.now() - last > ` + strconv.Itoa(interval) + `) refresh();
+	});
+})();</script>`
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/goravel/framework/contracts/http"
+	"github.com/samehelhawary/goravel-breeze/app/http/views"
+	"github.com/samehelhawary/goravel-breeze/testkit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var renderedField = regexp.MustCompile(`csrf_field: <input type="hidden" name="_token" value="([^"]+)">`)
+
+func TestInjectCSRFToViewsKeepsTokensPerRequest(t *testing.T) {
+	for _, driver := range testkit.Drivers {
+		t.Run(string(driver), func(t *testing.T) {
+			app := newApp(t, nil)
+			sessions := testkit.NewSessions(nil)
+			router := app.Route(t, driver)
+			router.Middleware(sessions.Middleware(), GenerateCSRFToken(), InjectCSRFToViews()).Get("/form", func(ctx http.Context) http.Response {
+				return views.Make(ctx, "form")
+			})
+
+			var tokens []string
+			for range 2 {
+				response := testkit.NewClient(t, router).Get("/form")
+				require.Equal(t, http.StatusOK, response.StatusCode)
+
+				token, _ := sessions.Current().Get("csrf_token").(string)
+				require.NotEmpty(t, token)
+				assert.Contains(t, response.Body, "csrf_token: "+token+"\n")
+
+				field := renderedField.FindStringSubmatch(response.Body)
+				require.Len(t, field, 2, response.Body)
+				unmasked, ok := unmaskToken(field[1])
+				assert.True(t, ok)
+				assert.Equal(t, token, unmasked)
+
+				tokens = append(tokens, token)
+			}
+
+			assert.NotEqual(t, tokens[0], tokens[1])
+			assert.Empty(t, app.View().GetShared(), "request data must not be shared with every view")
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+
+	"github.com/samehelhawary/goravel-breeze/crypt"
+)
+
+// LaravelCookieValuePrefix is the CookieValuePrefix scheme used by Laravel: a
+// hex encoded HMAC-SHA1 of the cookie name and "v2".
+type LaravelCookieValuePrefix struct {
+	key string
+}
+
+func NewLaravelCookieValuePrefix(key string) *LaravelCookieValuePrefix {
+	return &LaravelCookieValuePrefix{key: key}
+}
+
+func (c *LaravelCookieValuePrefix) Create(name string) string {
+	h := hmac.New(sha1.New, []byte(c.key))
+	h.Write([]byte(name + "v2"))
+	return hex.EncodeToString(h.Sum(nil)) + "|"
+}
+
+func (c *LaravelCookieValuePrefix) Match(name, value string, allKeys []string) (string, bool, error) {
+	for i, key := range allKeys {
+		prefix := NewLaravelCookieValuePrefix(key).Create(name)
+		if len(value) >= len(prefix) && hmac.Equal([]byte(value[:len(prefix)]), []byte(prefix)) {
+			return value[len(prefix):], i > 0, nil
+		}
+	}
+	return "", false, &crypt.DecryptException{Reason: CookieBadPrefix, Err: errors.New("invalid cookie prefix signature")}
+}
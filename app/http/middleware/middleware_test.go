@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"testing"
+
+	breeze "github.com/samehelhawary/goravel-breeze"
+	"github.com/samehelhawary/goravel-breeze/testkit"
+)
+
+// newApp creates a test application with the Breeze bindings registered, as
+// the middleware resolve their encrypter through the Breeze facade.
+func newApp(t testing.TB, settings map[string]any) *testkit.App {
+	t.Helper()
+
+	app := testkit.New(t, settings)
+	previousApp := breeze.App
+	t.Cleanup(func() {
+		breeze.App = previousApp
+	})
+	(&breeze.ServiceProvider{}).Register(app)
+
+	return app
+}
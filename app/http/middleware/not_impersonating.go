@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/samehelhawary/goravel-breeze/app/exceptions"
+)
+
+// NotImpersonating blocks sensitive routes, such as credential management,
+// while an administrator is impersonating another user.
+func NotImpersonating() http.Middleware {
+	return func(ctx http.Context) {
+		if ctx.Request().Session().Get("impersonator_id") != nil {
+			exceptions.Halt(ctx, http.StatusForbidden, "This action is not available while impersonating a user.")
+			return
+		}
+		ctx.Request().Next()
+	}
+}
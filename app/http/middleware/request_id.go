@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"regexp"
+
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/support/str"
+	"github.com/samehelhawary/goravel-breeze/app/exceptions"
+)
+
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// RequestID assigns every request an ID, reusing a well-formed X-Request-ID
+// header set by a proxy. The ID is echoed in the response and used by the
+// error pages and logs to correlate failures.
+func RequestID() http.Middleware {
+	return func(ctx http.Context) {
+		id := ctx.Request().Header("X-Request-ID")
+		if !requestIDPattern.MatchString(id) {
+			id = str.Random(32)
+		}
+
+		ctx.WithValue(exceptions.RequestIDKey, id)
+		ctx.Response().Header("X-Request-ID", id)
+		ctx.Request().Next()
+	}
+}
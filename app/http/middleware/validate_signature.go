@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/samehelhawary/goravel-breeze/app/exceptions"
+	"github.com/samehelhawary/goravel-breeze/app/http/signed"
+)
+
+// ValidateSignature rejects requests to signed routes whose signature is
+// missing, tampered with or expired.
+func ValidateSignature() http.Middleware {
+	return func(ctx http.Context) {
+		valid, err := signed.Valid(ctx)
+		if err != nil {
+			exceptions.Send(ctx, exceptions.Render(ctx, http.StatusInternalServerError, err))
+			return
+		}
+		if !valid {
+			exceptions.Halt(ctx, http.StatusForbidden, "This link is invalid or has expired.")
+			return
+		}
+		ctx.Request().Next()
+	}
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goravel/framework/contracts/http"
+	"github.com/samehelhawary/goravel-breeze/app/http/signed"
+	"github.com/samehelhawary/goravel-breeze/testkit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSignature(t *testing.T) {
+	newApp(t, nil)
+	link, err := signed.URL("/email/confirm", url.Values{"user": {"1"}}, time.Hour)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		settings map[string]any
+		link     string
+		status   int
+	}{
+		{"signed", nil, link, http.StatusOK},
+		{"forged", nil, strings.Replace(link, "user=1", "user=2", 1), http.StatusForbidden},
+		{"invalid key", map[string]any{"breeze.encryption.key": "too-short"}, link, http.StatusInternalServerError},
+	}
+
+	for _, driver := range testkit.Drivers {
+		for _, test := range tests {
+			t.Run(string(driver)+"/"+test.name, func(t *testing.T) {
+				router := newApp(t, test.settings).Route(t, driver)
+				router.Middleware(ValidateSignature()).Get("/email/confirm", func(ctx http.Context) http.Response {
+					return ctx.Response().String(http.StatusOK, "reached")
+				})
+
+				response := testkit.NewClient(t, router).Get(test.link)
+				assert.Equal(t, test.status, response.StatusCode)
+				assert.Equal(t, test.status == http.StatusOK, response.Body == "reached")
+			})
+		}
+	}
+}
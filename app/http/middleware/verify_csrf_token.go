@@ -2,89 +2,183 @@
 package middleware
 
 import (
+	"slices"
+	"strings"
+
 	"github.com/goravel/framework/contracts/http"
 )
 
-// VerifyCSRFToken with excluded paths
-func VerifyCSRFToken(excludedPaths ...string) http.Middleware {
-	manager := NewCSRFManager()
+// TokenSource extracts a submitted CSRF token from the request, returning an
+// empty string when it has none.
+type TokenSource func(ctx http.Context) string
 
-	return func(ctx http.Context) {
-		// Skip for excluded paths
-		currentPath := ctx.Request().Path()
-		for _, path := range excludedPaths {
-			if currentPath == path {
-				ctx.Request().Next()
-				return
-			}
-		}
-
-		// Skip CSRF for GET, HEAD, OPTIONS requests
-		method := ctx.Request().Method()
-		if method == "GET" || method == "HEAD" || method == "OPTIONS" {
-			ctx.Request().Next()
-			return
-		}
+// HeaderToken reads the token from a request header.
+func HeaderToken(name string) TokenSource {
+	return func(ctx http.Context) string {
+		return ctx.Request().Header(name)
+	}
+}
 
-		// Get token from request
-		token := manager.getTokenFromRequest(ctx)
+// InputToken reads the token from a form or JSON input field.
+func InputToken(name string) TokenSource {
+	return func(ctx http.Context) string {
+		return ctx.Request().Input(name)
+	}
+}
 
-		// Get session ID
-		sessionID := ctx.Request().Session().GetID()
-		if sessionID == "" {
-			ctx.Request().AbortWithStatusJson(http.StatusForbidden, http.Json{
-				"error": "Session not found",
-			})
-			return
+// XSRFHeaderToken reads the X-XSRF-TOKEN header (common in SPAs), which holds
+// the encrypted XSRF-TOKEN cookie.
+func XSRFHeaderToken(cookies *EncryptCookies) TokenSource {
+	return func(ctx http.Context) string {
+		token := ctx.Request().Header("X-XSRF-TOKEN")
+		if token == "" {
+			return ""
 		}
 
-		// Validate token
-		storedToken := manager.cache.Get(manager.getCacheKey(sessionID))
-		if storedToken == nil || storedToken.(string) != token {
-			ctx.Request().AbortWithStatusJson(http.StatusForbidden, http.Json{
-				"error": "CSRF token mismatch",
-			})
-			return
+		decrypted, err := cookies.Decrypt(XSRFCookie, token)
+		if err != nil {
+			return ""
 		}
 
-		ctx.Request().Next()
+		return decrypted
 	}
 }
 
-// CSRFForAPI for API routes with different error format
-func CSRFForAPI() http.Middleware {
+// CSRFVerifier verifies CSRF tokens. Requests matching the configured
+// exclusions or those added through Except, and requests using a safe
+// method, are skipped.
+type CSRFVerifier struct {
+	manager     *CSRFManager
+	except      []string
+	safeMethods []string
+	sources     []TokenSource
+	responder   CSRFResponder
+	originOnly  bool
+}
+
+func NewCSRFVerifier() *CSRFVerifier {
 	manager := NewCSRFManager()
 
-	return func(ctx http.Context) {
-		// Skip CSRF for safe methods
-		method := ctx.Request().Method()
-		if method == "GET" || method == "HEAD" || method == "OPTIONS" {
-			ctx.Request().Next()
-			return
-		}
+	return &CSRFVerifier{
+		manager:     manager,
+		except:      append([]string{}, manager.except...),
+		safeMethods: []string{"GET", "HEAD", "OPTIONS"},
+		sources: []TokenSource{
+			HeaderToken("X-CSRF-TOKEN"),
+			InputToken("_token"),
+			XSRFHeaderToken(manager.cookies),
+		},
+		responder: HTMLResponder,
+	}
+}
 
-		token := manager.getTokenFromRequest(ctx)
-		sessionID := ctx.Request().Session().GetID()
+// Except adds path, URL or route name patterns that skip verification, such
+// as "/webhooks/*" for payment provider callbacks.
+func (v *CSRFVerifier) Except(patterns ...string) *CSRFVerifier {
+	v.except = append(v.except, patterns...)
+	return v
+}
 
-		if sessionID == "" || token == "" {
-			ctx.Request().AbortWithStatusJson(http.StatusUnauthorized, http.Json{
-				"success": false,
-				"message": "CSRF token required",
-				"code":    "CSRF_TOKEN_MISSING",
-			})
-			return
-		}
+// SafeMethods replaces the methods that skip verification.
+func (v *CSRFVerifier) SafeMethods(methods ...string) *CSRFVerifier {
+	v.safeMethods = methods
+	return v
+}
+
+// TokenSources replaces where the submitted token is read from. Sources are
+// tried in order and the first non-empty token is used.
+func (v *CSRFVerifier) TokenSources(sources ...TokenSource) *CSRFVerifier {
+	v.sources = sources
+	return v
+}
+
+// RespondWith replaces how failed requests are answered, e.g. with
+// JSONResponder, APIResponder or a custom CSRFResponder.
+func (v *CSRFVerifier) RespondWith(responder CSRFResponder) *CSRFVerifier {
+	v.responder = responder
+	return v
+}
 
-		storedToken := manager.cache.Get(manager.getCacheKey(sessionID))
-		if storedToken == nil || storedToken.(string) != token {
-			ctx.Request().AbortWithStatusJson(http.StatusUnauthorized, http.Json{
-				"success": false,
-				"message": "Invalid CSRF token",
-				"code":    "CSRF_TOKEN_INVALID",
-			})
+// OriginOnly accepts requests without a token when the browser reports them
+// as sent from this application or a breeze.csrf.trusted_origins origin,
+// through the Sec-Fetch-Site, Origin or Referer headers. It suits JSON
+// endpoints called by a frontend that does not send tokens. Requests without
+// these headers still need a token.
+func (v *CSRFVerifier) OriginOnly() *CSRFVerifier {
+	v.originOnly = true
+	return v
+}
+
+// Handle returns the verification middleware.
+func (v *CSRFVerifier) Handle() http.Middleware {
+	return func(ctx http.Context) {
+		if failure, ok := v.verify(ctx); !ok {
+			v.responder(ctx, failure)
 			return
 		}
 
 		ctx.Request().Next()
 	}
 }
+
+func (v *CSRFVerifier) verify(ctx http.Context) (CSRFFailure, bool) {
+	// Skip for excluded paths
+	if inExceptArray(ctx, v.except) {
+		return "", true
+	}
+
+	// Skip for safe methods
+	if slices.ContainsFunc(v.safeMethods, func(method string) bool {
+		return strings.EqualFold(method, ctx.Request().Method())
+	}) {
+		return "", true
+	}
+
+	// Reject requests from foreign origins
+	if !v.manager.origins.allows(ctx) {
+		return CSRFOriginRejected, false
+	}
+	if v.originOnly && v.manager.origins.vouches(ctx) {
+		return "", true
+	}
+
+	if ctx.Request().Session().GetID() == "" {
+		return CSRFSessionMissing, false
+	}
+
+	token := v.token(ctx)
+	if token == "" {
+		return CSRFTokenMissing, false
+	}
+
+	if !tokensMatch(v.manager.store.Get(ctx), token) {
+		return CSRFTokenMismatch, false
+	}
+
+	return "", true
+}
+
+func (v *CSRFVerifier) token(ctx http.Context) string {
+	for _, source := range v.sources {
+		if token := source(ctx); token != "" {
+			return token
+		}
+	}
+
+	return ""
+}
+
+// CSRF verifies tokens on web routes, answering failures with HTMLResponder.
+func CSRF() http.Middleware {
+	return NewCSRFVerifier().Handle()
+}
+
+// VerifyCSRFToken with excluded paths
+func VerifyCSRFToken(excludedPaths ...string) http.Middleware {
+	return NewCSRFVerifier().Except(excludedPaths...).Handle()
+}
+
+// CSRFForAPI for API routes with different error format
+func CSRFForAPI() http.Middleware {
+	return NewCSRFVerifier().RespondWith(APIResponder).Handle()
+}
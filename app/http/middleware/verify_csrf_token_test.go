@@ -0,0 +1,280 @@
+package middleware
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/goravel/framework/contracts/http"
+	"github.com/samehelhawary/goravel-breeze/testkit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// csrfPresets are the ready-made CSRF middleware, built once the test
+// application exists since they read its configuration.
+var csrfPresets = map[string]func() http.Middleware{
+	"CSRF":            CSRF,
+	"VerifyCSRFToken": func() http.Middleware { return VerifyCSRFToken() },
+	"CSRFForAPI":      CSRFForAPI,
+}
+
+// csrfStore is the breeze.csrf.store every csrfClient uses, see
+func TestCSRFOriginOnly(t *testing.T) {
+	originOnly := func() http.Middleware {
+		return NewCSRFVerifier().OriginOnly().RespondWith(APIResponder).Handle()
+	}
+	settings := map[string]any{"breeze.csrf.trusted_origins": "https://spa.example"}
+
+	tests := []struct {
+		name     string
+		settings map[string]any
+		headers  map[string]string
+		status   int
+		body     string
+	}{
+		{"trusted origin", settings, map[string]string{"Origin": "https://spa.example"}, http.StatusOK, "reached"},
+		{"trusted referer", settings, map[string]string{"Referer": "https://spa.example/checkout"}, http.StatusOK, "reached"},
+		{"same origin", nil, map[string]string{"Origin": "http://localhost"}, http.StatusOK, "reached"},
+		{"same origin fetch", nil, map[string]string{"Sec-Fetch-Site": "same-origin"}, http.StatusOK, "reached"},
+		{"without origin headers", settings, nil, http.StatusUnauthorized, `"code":"CSRF_TOKEN_MISSING"`},
+		{"other scheme", settings, map[string]string{"Origin": "http://spa.example"}, http.StatusUnauthorized, `"code":"CSRF_TOKEN_MISSING"`},
+		{"foreign origin", map[string]any{"breeze.csrf.check_origin": true}, map[string]string{"Origin": "https://evil.example"}, http.StatusForbidden, `"code":"CSRF_ORIGIN_REJECTED"`},
+	}
+
+	for _, driver := range testkit.Drivers {
+		for _, test := range tests {
+			t.Run(string(driver)+"/"+test.name, func(t *testing.T) {
+				client := newCSRFClient(t, driver, test.settings, originOnly)
+
+				response := client.post("/form", nil, test.headers)
+				assert.Equal(t, test.status, response.StatusCode)
+				assert.Contains(t, response.Body, test.body)
+			})
+		}
+	}
+}
+
+// TestCSRFWithTheCacheTokenStore.
+var csrfStore = "session"
+
+// csrfClient serves /form and /webhooks/stripe behind the web middleware of
+// the kernel stub and the given CSRF middleware. It has already visited the
+// form, so its session holds a token and the client an XSRF-TOKEN cookie.
+type csrfClient struct {
+	*testkit.Client
+	token string
+}
+
+func newCSRFClient(t *testing.T, driver testkit.Driver, settings map[string]any, csrf func() http.Middleware) *csrfClient {
+	t.Helper()
+
+	withStore := map[string]any{"breeze.csrf.store": csrfStore}
+	for key, value := range settings {
+		withStore[key] = value
+	}
+	app := newApp(t, withStore)
+	sessions := testkit.NewSessions(nil)
+	router := app.Route(t, driver)
+	reached := func(ctx http.Context) http.Response {
+		return ctx.Response().String(http.StatusOK, "reached")
+	}
+	web := []http.Middleware{sessions.Middleware(), NewEncryptCookies().DisableFor(testkit.SessionCookie).Handle(), GenerateCSRFToken()}
+
+	router.Middleware(append(web, csrf())...).Any("/form", reached)
+	router.Middleware(append(web, csrf())...).Post("/webhooks/stripe", reached)
+	router.Middleware(append(web, RouteName("payments.callback"), csrf())...).Post("/payments/callback", reached)
+
+	client := &csrfClient{Client: testkit.NewClient(t, router)}
+	require.Equal(t, http.StatusOK, client.Get("/form").StatusCode)
+	session := sessions.Current()
+	if csrfStore == "cache" {
+		assert.Nil(t, session.Get("csrf_token"), "the token must not be kept in the session")
+		client.token = app.MakeCache().GetString("csrf_token:" + session.GetID())
+	} else {
+		client.token, _ = session.Get("csrf_token").(string)
+	}
+	require.NotEmpty(t, client.token)
+
+	return client
+}
+
+func (c *csrfClient) post(path string, form url.Values, headers map[string]string) *testkit.Response {
+	request := testkit.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+
+	return c.Do(request)
+}
+
+func TestCSRFPresetsSkipSafeMethods(t *testing.T) {
+	for _, driver := range testkit.Drivers {
+		for name, preset := range csrfPresets {
+			t.Run(string(driver)+"/"+name, func(t *testing.T) {
+				client := newCSRFClient(t, driver, nil, preset)
+
+				for _, method := range []string{http.MethodGet, http.MethodHead, http.MethodOptions} {
+					response := client.Do(testkit.NewRequest(method, "/form", nil))
+					assert.Equal(t, http.StatusOK, response.StatusCode, method)
+				}
+				assert.NotEqual(t, http.StatusOK, client.post("/form", nil, nil).StatusCode)
+			})
+		}
+	}
+}
+
+func TestCSRFPresetsAcceptEveryTokenSource(t *testing.T) {
+	tests := []struct {
+		name    string
+		form    func(client *csrfClient) url.Values
+		headers func(client *csrfClient) map[string]string
+	}{
+		{
+			name: "form field",
+			form: func(client *csrfClient) url.Values { return url.Values{"_token": {client.token}} },
+		},
+		{
+			name: "masked form field",
+			form: func(client *csrfClient) url.Values { return url.Values{"_token": {MaskToken(client.token)}} },
+		},
+		{
+			name: "X-CSRF-TOKEN header",
+			headers: func(client *csrfClient) map[string]string {
+				return map[string]string{"X-CSRF-TOKEN": MaskToken(client.token)}
+			},
+		},
+		{
+			name: "X-XSRF-TOKEN header",
+			headers: func(client *csrfClient) map[string]string {
+				// Axios and Angular send the URL decoded cookie value
+				token, _ := url.PathUnescape(client.Cookie(XSRFCookie))
+				return map[string]string{"X-XSRF-TOKEN": token}
+			},
+		},
+	}
+
+	for _, driver := range testkit.Drivers {
+		for name, preset := range csrfPresets {
+			for _, test := range tests {
+				t.Run(string(driver)+"/"+name+"/"+test.name, func(t *testing.T) {
+					client := newCSRFClient(t, driver, nil, preset)
+
+					var form url.Values
+					var headers map[string]string
+					if test.form != nil {
+						form = test.form(client)
+					}
+					if test.headers != nil {
+						headers = test.headers(client)
+					}
+
+					response := client.post("/form", form, headers)
+					assert.Equal(t, http.StatusOK, response.StatusCode)
+					assert.Equal(t, "reached", response.Body)
+				})
+			}
+		}
+	}
+
+	t.Run("xsrf cookie is not a token", func(t *testing.T) {
+		client := newCSRFClient(t, testkit.Gin, nil, CSRFForAPI)
+
+		response := client.post("/form", nil, map[string]string{"X-XSRF-TOKEN": client.token})
+		assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	})
+}
+
+func TestCSRFExclusions(t *testing.T) {
+	settings := map[string]any{"breeze.csrf.except": []string{"/webhooks/*"}}
+	tests := []struct {
+		name     string
+		csrf     func() http.Middleware
+		path     string
+		excluded bool
+	}{
+		{"configured path", CSRF, "/webhooks/stripe", true},
+		{"not excluded", CSRF, "/form", false},
+		{"preset path", func() http.Middleware { return VerifyCSRFToken("/payments/*") }, "/payments/callback", true},
+		{"full url", func() http.Middleware { return NewCSRFVerifier().Except("http://localhost/payments/*").Handle() }, "/payments/callback", true},
+		{"other host", func() http.Middleware { return NewCSRFVerifier().Except("https://example.com/*").Handle() }, "/payments/callback", false},
+		{"route name", func() http.Middleware { return NewCSRFVerifier().Except("payments.*").Handle() }, "/payments/callback", true},
+		{"api preset keeps configured paths", CSRFForAPI, "/webhooks/stripe", true},
+	}
+
+	for _, driver := range testkit.Drivers {
+		for _, test := range tests {
+			t.Run(string(driver)+"/"+test.name, func(t *testing.T) {
+				client := newCSRFClient(t, driver, settings, test.csrf)
+
+				response := client.post(test.path, nil, nil)
+				if test.excluded {
+					assert.Equal(t, http.StatusOK, response.StatusCode)
+				} else {
+					assert.NotEqual(t, http.StatusOK, response.StatusCode)
+				}
+			})
+		}
+	}
+}
+
+func TestCSRFResponders(t *testing.T) {
+	custom := func() http.Middleware {
+		return NewCSRFVerifier().RespondWith(func(ctx http.Context, failure CSRFFailure) {
+			ctx.Request().AbortWithStatusJson(http.StatusTeapot, http.Json{"failure": failure})
+		}).Handle()
+	}
+	jsonOnly := func() http.Middleware {
+		return NewCSRFVerifier().RespondWith(JSONResponder).Handle()
+	}
+
+	tests := []struct {
+		name    string
+		csrf    func() http.Middleware
+		form    url.Values
+		headers map[string]string
+		status  int
+		body    string
+	}{
+		{"html missing token", CSRF, nil, nil, 419, "view: errors/419"},
+		{"html mismatch", CSRF, url.Values{"_token": {"forged"}}, nil, 419, "view: errors/419"},
+		{"html foreign origin", CSRF, nil, map[string]string{"Origin": "https://evil.example"}, http.StatusForbidden, "view: errors/403"},
+		{"html from xhr", CSRF, nil, map[string]string{"Accept": "application/json"}, http.StatusForbidden, `{"error":"CSRF token mismatch"}`},
+		{"json missing token", jsonOnly, nil, nil, http.StatusForbidden, `{"error":"CSRF token mismatch"}`},
+		{"json foreign origin", jsonOnly, nil, map[string]string{"Origin": "https://evil.example"}, http.StatusForbidden, `{"error":"Cross-origin request rejected"}`},
+		{"api missing token", CSRFForAPI, nil, nil, http.StatusUnauthorized, `"code":"CSRF_TOKEN_MISSING"`},
+		{"api mismatch", CSRFForAPI, url.Values{"_token": {"forged"}}, nil, http.StatusUnauthorized, `"code":"CSRF_TOKEN_INVALID"`},
+		{"api foreign origin", CSRFForAPI, nil, map[string]string{"Origin": "https://evil.example"}, http.StatusForbidden, `"code":"CSRF_ORIGIN_REJECTED"`},
+		{"custom missing token", custom, nil, nil, http.StatusTeapot, `{"failure":"token_missing"}`},
+		{"custom mismatch", custom, url.Values{"_token": {"forged"}}, nil, http.StatusTeapot, `{"failure":"token_mismatch"}`},
+		{"custom foreign origin", custom, nil, map[string]string{"Origin": "https://evil.example"}, http.StatusTeapot, `{"failure":"origin_rejected"}`},
+	}
+
+	for _, driver := range testkit.Drivers {
+		for _, test := range tests {
+			t.Run(string(driver)+"/"+test.name, func(t *testing.T) {
+				client := newCSRFClient(t, driver, map[string]any{"breeze.csrf.check_origin": true}, test.csrf)
+
+				response := client.post("/form", test.form, test.headers)
+				assert.Equal(t, test.status, response.StatusCode)
+				assert.Contains(t, response.Body, test.body)
+				assert.NotContains(t, response.Body, "reached")
+			})
+		}
+	}
+}
+
+// TestCSRFWithTheCacheTokenStore runs the CSRF tests again with the tokens
+// kept in the cache rather than in the session.
+func TestCSRFWithTheCacheTokenStore(t *testing.T) {
+	csrfStore = "cache"
+	t.Cleanup(func() {
+		csrfStore = "session"
+	})
+
+	t.Run("SkipSafeMethods", TestCSRFPresetsSkipSafeMethods)
+	t.Run("AcceptEveryTokenSource", TestCSRFPresetsAcceptEveryTokenSource)
+	t.Run("Exclusions", TestCSRFExclusions)
+	t.Run("Responders", TestCSRFResponders)
+}
@@ -0,0 +1,55 @@
+package requests
+
+import (
+	"testing"
+
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type filtered interface {
+	Filters(ctx http.Context) map[string]string
+}
+
+// Emails and usernames are stored in lower case and compared as they are,
+// so every request writing or looking them up must lowercase them.
+func TestRequestsLowercaseIdentifiers(t *testing.T) {
+	register := &StoreRegisterRequest{}
+	login := &StoreAuthRequest{}
+	invitation := &StoreInvitationRequest{}
+	profile := &UpdateProfileRequest{}
+
+	tests := []struct {
+		name    string
+		request filtered
+		fields  []string
+		bound   func() []string
+	}{
+		{"register", register, []string{"email", "username"}, func() []string { return []string{register.Email, register.Username} }},
+		{"login", login, []string{"email"}, func() []string { return []string{login.Email} }},
+		{"invitation", invitation, []string{"email"}, func() []string { return []string{invitation.Email} }},
+		{"profile", profile, []string{"email"}, func() []string { return []string{profile.Email} }},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data := map[string]any{}
+			rules := map[string]string{}
+			for _, field := range test.fields {
+				data[field] = "  Jane.Doe@Example.COM "
+				rules[field] = "required"
+			}
+
+			validator, err := validation.NewValidation().Make(data, rules, validation.Filters(test.request.Filters(nil)))
+			require.NoError(t, err)
+			require.False(t, validator.Fails())
+			require.NoError(t, validator.Bind(test.request))
+
+			for _, value := range test.bound() {
+				assert.Equal(t, "jane.doe@example.com", value)
+			}
+		})
+	}
+}
@@ -1,8 +1,11 @@
 package requests
 
 import (
+	"strings"
+
 	"github.com/goravel/framework/contracts/http"
 	"github.com/goravel/framework/contracts/validation"
+	"github.com/goravel/framework/facades"
 )
 
 type StoreAuthRequest struct {
@@ -11,20 +14,52 @@ type StoreAuthRequest struct {
 	Remember string `form:"remember" json:"remember"`
 }
 
+// LoginIdentifier returns the configured login identifier: "email",
+// "username" or "either".
+func LoginIdentifier() string {
+	switch identifier := facades.Config().GetString("breeze.login.identifier", "email"); identifier {
+	case "username", "either":
+		return identifier
+	default:
+		return "email"
+	}
+}
+
+// Identifier returns the users column the submitted login should be matched
+// against. In "either" mode it is detected from the input itself.
+func (r *StoreAuthRequest) Identifier() string {
+	switch LoginIdentifier() {
+	case "username":
+		return "username"
+	case "either":
+		if strings.Contains(r.Email, "@") {
+			return "email"
+		}
+		return "username"
+	default:
+		return "email"
+	}
+}
+
 func (r *StoreAuthRequest) Authorize(ctx http.Context) error {
 	return nil
 }
 
 func (r *StoreAuthRequest) Filters(ctx http.Context) map[string]string {
 	return map[string]string{
-		"email":    "trim",
+		"email":    "trim|lower",
 		"password": "trim",
 	}
 }
 
 func (r *StoreAuthRequest) Rules(ctx http.Context) map[string]string {
+	email := "required|email"
+	if LoginIdentifier() != "email" {
+		email = "required|max_len:255"
+	}
+
 	return map[string]string{
-		"email":    "required|email",
+		"email":    email,
 		"password": "required",
 	}
 }
@@ -34,7 +69,14 @@ func (r *StoreAuthRequest) Messages(ctx http.Context) map[string]string {
 }
 
 func (r *StoreAuthRequest) Attributes(ctx http.Context) map[string]string {
-	return map[string]string{}
+	switch LoginIdentifier() {
+	case "username":
+		return map[string]string{"email": "username"}
+	case "either":
+		return map[string]string{"email": "email or username"}
+	default:
+		return map[string]string{}
+	}
 }
 
 func (r *StoreAuthRequest) PrepareForValidation(ctx http.Context, data validation.Data) error {
@@ -0,0 +1,38 @@
+package requests
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/contracts/validation"
+)
+
+type StoreInvitationRequest struct {
+	Email string `form:"email" json:"email"`
+}
+
+func (r *StoreInvitationRequest) Authorize(ctx http.Context) error {
+	return nil
+}
+
+func (r *StoreInvitationRequest) Filters(ctx http.Context) map[string]string {
+	return map[string]string{
+		"email": "trim|lower",
+	}
+}
+
+func (r *StoreInvitationRequest) Rules(ctx http.Context) map[string]string {
+	return map[string]string{
+		"email": "required|email|unique:users,email",
+	}
+}
+
+func (r *StoreInvitationRequest) Messages(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *StoreInvitationRequest) Attributes(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *StoreInvitationRequest) PrepareForValidation(ctx http.Context, data validation.Data) error {
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 
 type StoreRegisterRequest struct {
 	Name            string `form:"name" json:"name"`
+	Username        string `form:"username" json:"username"`
 	Email           string `form:"email" json:"email"`
 	Password        string `form:"password" json:"password"`
 	PasswordConfirm string `form:"password_confirmation" json:"password_confirmation"`
@@ -19,15 +20,22 @@ func (r *StoreRegisterRequest) Authorize(ctx http.Context) error {
 func (r *StoreRegisterRequest) Filters(ctx http.Context) map[string]string {
 	return map[string]string{
 		"name":                  "trim",
-		"email":                 "trim",
+		"username":              "trim|lower",
+		"email":                 "trim|lower",
 		"password":              "trim",
 		"password_confirmation": "trim",
 	}
 }
 
 func (r *StoreRegisterRequest) Rules(ctx http.Context) map[string]string {
+	username := "max_len:255|alpha_dash|unique:users,username"
+	if LoginIdentifier() == "username" {
+		username = "required|" + username
+	}
+
 	return map[string]string{
 		"name":                  "required|max_len:255",
+		"username":              username,
 		"email":                 "required|email|unique:users,email",
 		"password":              "required|confirmed:password",
 		"password_confirmation": "required",
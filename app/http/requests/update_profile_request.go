@@ -0,0 +1,43 @@
+package requests
+
+import (
+	"fmt"
+
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/contracts/validation"
+)
+
+type UpdateProfileRequest struct {
+	Name  string `form:"name" json:"name"`
+	Email string `form:"email" json:"email"`
+}
+
+func (r *UpdateProfileRequest) Authorize(ctx http.Context) error {
+	return nil
+}
+
+func (r *UpdateProfileRequest) Filters(ctx http.Context) map[string]string {
+	return map[string]string{
+		"name":  "trim",
+		"email": "trim|lower",
+	}
+}
+
+func (r *UpdateProfileRequest) Rules(ctx http.Context) map[string]string {
+	return map[string]string{
+		"name":  "required|max_len:255",
+		"email": fmt.Sprintf("required|email|unique:users,email,%v", ctx.Request().Session().Get("user_id")),
+	}
+}
+
+func (r *UpdateProfileRequest) Messages(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *UpdateProfileRequest) Attributes(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *UpdateProfileRequest) PrepareForValidation(ctx http.Context, data validation.Data) error {
+	return nil
+}
@@ -0,0 +1,93 @@
+// Package signed creates and verifies tamper-proof, expiring URLs such as the
+// links sent in confirmation emails.
+package signed
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/facades"
+	breeze "github.com/samehelhawary/goravel-breeze/facades"
+)
+
+// URL returns an absolute URL for path carrying params, an expiry timestamp
+// and an HMAC signature of all of them. It fails when the Breeze key is
+// invalid, rather than signing with a key anyone could guess.
+func URL(path string, params url.Values, ttl time.Duration) (string, error) {
+	signingKeys, err := keys()
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{}
+	for key, values := range params {
+		query[key] = values
+	}
+	query.Set("expires", strconv.FormatInt(time.Now().Add(ttl).Unix(), 10))
+	query.Set("signature", sign(signingKeys[0], path, query))
+
+	return strings.TrimRight(facades.Config().GetString("http.url"), "/") + path + "?" + query.Encode(), nil
+}
+
+// Valid reports whether the current request carries a valid, unexpired
+// signature. It fails when the Breeze key is invalid, so no link is trusted.
+func Valid(ctx http.Context) (bool, error) {
+	signingKeys, err := keys()
+	if err != nil {
+		return false, err
+	}
+
+	query := url.Values{}
+	for key, value := range ctx.Request().Queries() {
+		query.Set(key, value)
+	}
+	signature := query.Get("signature")
+	if signature == "" {
+		return false, nil
+	}
+
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return false, nil
+	}
+
+	// Links signed with a previous key stay valid until they expire
+	for _, key := range signingKeys {
+		if hmac.Equal([]byte(signature), []byte(sign(key, ctx.Request().Path(), query))) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// keys returns the keys of the shared encrypter, current key first.
+func keys() ([]string, error) {
+	encrypter, err := breeze.Breeze().Encrypter()
+	if err != nil {
+		return nil, fmt.Errorf("cannot sign URLs: %w", err)
+	}
+
+	return encrypter.GetAllKeys(), nil
+}
+
+func sign(key, path string, query url.Values) string {
+	unsigned := url.Values{}
+	for key, values := range query {
+		if key != "signature" {
+			unsigned[key] = values
+		}
+	}
+
+	h := hmac.New(sha256.New, []byte(key))
+	h.Write([]byte(path + "?" + unsigned.Encode()))
+
+	return hex.EncodeToString(h.Sum(nil))
+}
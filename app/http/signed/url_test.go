@@ -0,0 +1,120 @@
+package signed
+
+import (
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/goravel/framework/contracts/http"
+	breeze "github.com/samehelhawary/goravel-breeze"
+	"github.com/samehelhawary/goravel-breeze/testkit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const previousKey = "breeze-previous-key-of-32-bytes!"
+
+// newApp creates a test application with the Breeze service provider
+// registered, which binds the encrypter whose keys sign the URLs.
+func newApp(t *testing.T, settings map[string]any) *testkit.App {
+	t.Helper()
+
+	previousApp := breeze.App
+	t.Cleanup(func() {
+		breeze.App = previousApp
+	})
+	app := testkit.New(t, settings)
+	(&breeze.ServiceProvider{}).Register(app)
+
+	return app
+}
+
+// validate reports what Valid says about link on driver.
+func validate(t *testing.T, app *testkit.App, driver testkit.Driver, link string) string {
+	t.Helper()
+
+	router := app.Route(t, driver)
+	router.Get("/email/confirm", func(ctx http.Context) http.Response {
+		valid, err := Valid(ctx)
+		if err != nil {
+			return ctx.Response().String(http.StatusInternalServerError, err.Error())
+		}
+		return ctx.Response().String(http.StatusOK, strconv.FormatBool(valid))
+	})
+
+	return testkit.NewClient(t, router).Get(link).Body
+}
+
+func TestSignedURLs(t *testing.T) {
+	params := url.Values{"user": {"1"}, "email": {"new@example.com"}}
+
+	for _, driver := range testkit.Drivers {
+		t.Run(string(driver), func(t *testing.T) {
+			app := newApp(t, nil)
+			link, err := URL("/email/confirm", params, time.Hour)
+			require.NoError(t, err)
+			assert.Equal(t, "true", validate(t, app, driver, link))
+
+			parsed, err := url.Parse(link)
+			require.NoError(t, err)
+			query := parsed.Query()
+			query.Set("email", "attacker@example.com")
+			assert.Equal(t, "false", validate(t, app, driver, "/email/confirm?"+query.Encode()), "tampered")
+
+			query = parsed.Query()
+			query.Del("signature")
+			assert.Equal(t, "false", validate(t, app, driver, "/email/confirm?"+query.Encode()), "unsigned")
+
+			expired, err := URL("/email/confirm", params, -time.Minute)
+			require.NoError(t, err)
+			assert.Equal(t, "false", validate(t, app, driver, expired), "expired")
+		})
+	}
+}
+
+func TestSignedURLsOfPreviousKeysStayValid(t *testing.T) {
+	newApp(t, map[string]any{"breeze.encryption.key": previousKey})
+	link, err := URL("/email/confirm", url.Values{"user": {"1"}}, time.Hour)
+	require.NoError(t, err)
+
+	rotated := newApp(t, map[string]any{"breeze.encryption.previous_keys": previousKey})
+	assert.Equal(t, "true", validate(t, rotated, testkit.Gin, link))
+
+	current := newApp(t, nil)
+	assert.Equal(t, "false", validate(t, current, testkit.Gin, link))
+}
+
+func TestSignedURLsFailWithoutAValidKey(t *testing.T) {
+	app := newApp(t, nil)
+	link, err := URL("/email/confirm", url.Values{"user": {"1"}}, time.Hour)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		setup func(t *testing.T) *testkit.App
+	}{
+		{"invalid key", func(t *testing.T) *testkit.App {
+			return newApp(t, map[string]any{"breeze.encryption.key": ""})
+		}},
+		{"missing provider", func(t *testing.T) *testkit.App {
+			breeze.App = nil
+			return app
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			previousApp := breeze.App
+			t.Cleanup(func() {
+				breeze.App = previousApp
+			})
+			broken := test.setup(t)
+
+			// Nothing is signed with, nor accepted for, an empty key
+			_, err := URL("/email/confirm", url.Values{"user": {"1"}}, time.Hour)
+			assert.Error(t, err)
+			assert.Contains(t, validate(t, broken, testkit.Gin, link), "cannot sign URLs")
+		})
+	}
+}
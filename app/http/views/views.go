@@ -0,0 +1,49 @@
+// Package views renders views with data shared for the current request only,
+// such as the CSRF helpers, on top of the data shared through facades.View().
+// Values that depend on the request must never go through facades.View().Share,
+// which every request reads.
+package views
+
+import (
+	"github.com/goravel/framework/contracts/http"
+)
+
+// SharedKey is the context key holding the data shared for the request. It is
+// a plain string so published copies of this package see the same data.
+const SharedKey = "breeze_view_shared"
+
+// Share makes value available as key to the views rendered for this request.
+func Share(ctx http.Context, key string, value any) {
+	shared, ok := ctx.Value(SharedKey).(map[string]any)
+	if !ok {
+		shared = map[string]any{}
+		ctx.WithValue(SharedKey, shared)
+	}
+
+	shared[key] = value
+}
+
+// Shared returns a copy of the data shared for this request.
+func Shared(ctx http.Context) map[string]any {
+	shared, _ := ctx.Value(SharedKey).(map[string]any)
+
+	data := make(map[string]any, len(shared))
+	for key, value := range shared {
+		data[key] = value
+	}
+
+	return data
+}
+
+// Make renders view like ctx.Response().View().Make, adding the data shared
+// for this request. Keys given in data take precedence.
+func Make(ctx http.Context, view string, data ...map[string]any) http.Response {
+	merged := Shared(ctx)
+	for _, values := range data {
+		for key, value := range values {
+			merged[key] = value
+		}
+	}
+
+	return ctx.Response().View().Make(view, merged)
+}
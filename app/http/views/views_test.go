@@ -0,0 +1,31 @@
+package views
+
+import (
+	"testing"
+
+	"github.com/goravel/framework/contracts/http"
+	"github.com/samehelhawary/goravel-breeze/testkit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMakeMergesRequestAndGlobalData(t *testing.T) {
+	for _, driver := range testkit.Drivers {
+		t.Run(string(driver), func(t *testing.T) {
+			app := testkit.New(t, nil)
+			app.View().Share("site", "Breeze")
+			router := app.Route(t, driver)
+			router.Middleware(func(ctx http.Context) {
+				Share(ctx, "visitor", ctx.Request().Query("name"))
+				Share(ctx, "title", "shared")
+				ctx.Request().Next()
+			}).Get("/", func(ctx http.Context) http.Response {
+				return Make(ctx, "page", map[string]any{"title": "given"})
+			})
+
+			client := testkit.NewClient(t, router)
+			assert.Equal(t, "view: page\nsite: Breeze\ntitle: given\nvisitor: jane\n", client.Get("/?name=jane").Body)
+			assert.Equal(t, "view: page\nsite: Breeze\ntitle: given\nvisitor: john\n", client.Get("/?name=john").Body)
+			assert.NotContains(t, app.View().GetShared(), "visitor")
+		})
+	}
+}
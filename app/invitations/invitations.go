@@ -0,0 +1,106 @@
+// Package invitations issues and redeems the single-use tokens required to
+// register while registration runs in invite-only mode.
+package invitations
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/goravel/framework/contracts/database/orm"
+	"github.com/goravel/framework/facades"
+	"github.com/goravel/framework/support/carbon"
+	"github.com/samehelhawary/goravel-breeze/app/models"
+)
+
+// Registration modes supported by "breeze.registration.mode".
+const (
+	ModeOpen       = "open"
+	ModeClosed     = "closed"
+	ModeInviteOnly = "invite-only"
+)
+
+var (
+	ErrInvalid = errors.New("this invitation is invalid or has already been used")
+	ErrExpired = errors.New("this invitation has expired")
+)
+
+// Mode returns the configured registration mode, falling back to open
+// registration for unknown values.
+func Mode() string {
+	switch mode := strings.ToLower(facades.Config().GetString("breeze.registration.mode", ModeOpen)); mode {
+	case ModeClosed, ModeInviteOnly:
+		return mode
+	default:
+		return ModeOpen
+	}
+}
+
+// Issue stores a new invitation bound to email and returns the plain token.
+// Only the SHA-256 hash of the token is kept in the database.
+func Issue(email string, invitedBy *uint) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	lifetime := facades.Config().GetInt("breeze.registration.invitation_lifetime", 10080)
+	invitation := models.Invitation{
+		Email:     strings.ToLower(strings.TrimSpace(email)),
+		Token:     hash(token),
+		InvitedBy: invitedBy,
+		ExpiresAt: carbon.NewDateTime(carbon.Now().AddMinutes(lifetime)),
+	}
+	if err := facades.Orm().Query().Create(&invitation); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// Find returns the pending invitation for a plain token.
+func Find(token string) (*models.Invitation, error) {
+	if token == "" {
+		return nil, ErrInvalid
+	}
+
+	var invitation models.Invitation
+	if err := facades.Orm().Query().Where("token", hash(token)).WhereNull("accepted_at").First(&invitation); err != nil || invitation.ID == 0 {
+		return nil, ErrInvalid
+	}
+	if invitation.ExpiresAt.IsPast() {
+		return nil, ErrExpired
+	}
+
+	return &invitation, nil
+}
+
+// Accept marks the invitation as used so the token cannot be redeemed again.
+// It runs on query, normally the transaction creating the invited user, and
+// returns ErrInvalid when another request has already used the invitation.
+func Accept(query orm.Query, invitation *models.Invitation) error {
+	result, err := query.Model(&models.Invitation{}).Where("id", invitation.ID).WhereNull("accepted_at").Update("accepted_at", carbon.NewDateTime(carbon.Now()))
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected == 0 {
+		return ErrInvalid
+	}
+
+	return nil
+}
+
+// URL builds the registration link for a plain token.
+func URL(token string) string {
+	return strings.TrimRight(facades.Config().GetString("http.url"), "/") + "/register?token=" + url.QueryEscape(token)
+}
+
+func hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
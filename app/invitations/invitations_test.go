@@ -0,0 +1,41 @@
+package invitations
+
+import (
+	"errors"
+	"testing"
+
+	contractsorm "github.com/goravel/framework/contracts/database/orm"
+	mocksorm "github.com/goravel/framework/mocks/database/orm"
+	"github.com/samehelhawary/goravel-breeze/app/models"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAccept(t *testing.T) {
+	failure := errors.New("connection lost")
+	tests := []struct {
+		name     string
+		affected int64
+		err      error
+		want     error
+	}{
+		{"pending invitation", 1, nil, nil},
+		{"already used", 0, nil, ErrInvalid},
+		{"database error", 0, failure, failure},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query := mocksorm.NewQuery(t)
+			query.EXPECT().Model(&models.Invitation{}).Return(query).Once()
+			query.EXPECT().Where("id", uint(7)).Return(query).Once()
+			query.EXPECT().WhereNull("accepted_at").Return(query).Once()
+			query.EXPECT().Update("accepted_at", mock.Anything).Return(&contractsorm.Result{RowsAffected: test.affected}, test.err).Once()
+
+			invitation := &models.Invitation{}
+			invitation.ID = 7
+			if err := Accept(query, invitation); !errors.Is(err, test.want) {
+				t.Errorf("Accept() = %v, want %v", err, test.want)
+			}
+		})
+	}
+}
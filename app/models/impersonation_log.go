@@ -0,0 +1,14 @@
+package models
+
+import (
+	"github.com/goravel/framework/database/orm"
+)
+
+type ImpersonationLog struct {
+	orm.Model
+	ImpersonatorID uint
+	UserID         uint
+	Action         string
+	IpAddress      string
+	UserAgent      string
+}
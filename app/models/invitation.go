@@ -0,0 +1,15 @@
+package models
+
+import (
+	"github.com/goravel/framework/database/orm"
+	"github.com/goravel/framework/support/carbon"
+)
+
+type Invitation struct {
+	orm.Model
+	Email      string
+	Token      string
+	InvitedBy  *uint
+	ExpiresAt  carbon.DateTime  `gorm:"column:expires_at"`
+	AcceptedAt *carbon.DateTime `gorm:"column:accepted_at"`
+}
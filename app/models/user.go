@@ -7,7 +7,9 @@ import (
 type User struct {
 	orm.Model
 	Name          string
+	Username      *string
 	Email         string
+	PendingEmail  *string
 	Password      string
 	RememberToken string `gorm:"column:remember_token"`
 	orm.SoftDeletes
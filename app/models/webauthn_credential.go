@@ -0,0 +1,78 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/goravel/framework/database/orm"
+	"github.com/goravel/framework/support/carbon"
+)
+
+type WebauthnCredential struct {
+	orm.Model
+	UserID          uint
+	Name            string
+	CredentialID    string
+	CredentialHash  string
+	PublicKey       string
+	AttestationType string
+	Transports      string
+	AAGUID          string `gorm:"column:aaguid"`
+	SignCount       uint32
+	CloneWarning    bool
+	BackupEligible  bool
+	BackupState     bool
+	LastUsedAt      *carbon.DateTime `gorm:"column:last_used_at"`
+}
+
+// CredentialHash returns the fixed length key passkeys are looked up by.
+// Credential IDs can be up to 1023 bytes, too long for a unique index on
+// every database, so the index is on their SHA-256 instead.
+func CredentialHash(credentialID []byte) string {
+	sum := sha256.Sum256(credentialID)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// ToCredential rebuilds the webauthn credential record from the stored columns.
+func (c *WebauthnCredential) ToCredential() (webauthn.Credential, error) {
+	id, err := base64.RawURLEncoding.DecodeString(c.CredentialID)
+	if err != nil {
+		return webauthn.Credential{}, err
+	}
+	publicKey, err := base64.RawURLEncoding.DecodeString(c.PublicKey)
+	if err != nil {
+		return webauthn.Credential{}, err
+	}
+	aaguid, err := base64.RawURLEncoding.DecodeString(c.AAGUID)
+	if err != nil {
+		return webauthn.Credential{}, err
+	}
+
+	var transports []protocol.AuthenticatorTransport
+	for _, transport := range strings.Split(c.Transports, ",") {
+		if transport != "" {
+			transports = append(transports, protocol.AuthenticatorTransport(transport))
+		}
+	}
+
+	return webauthn.Credential{
+		ID:              id,
+		PublicKey:       publicKey,
+		AttestationType: c.AttestationType,
+		Transport:       transports,
+		Flags: webauthn.CredentialFlags{
+			BackupEligible: c.BackupEligible,
+			BackupState:    c.BackupState,
+		},
+		Authenticator: webauthn.Authenticator{
+			AAGUID:       aaguid,
+			SignCount:    c.SignCount,
+			CloneWarning: c.CloneWarning,
+		},
+	}, nil
+}
@@ -1,6 +1,8 @@
 package rules
 
 import (
+	"fmt"
+
 	"github.com/goravel/framework/contracts/validation"
 	"github.com/goravel/framework/facades"
 )
@@ -14,11 +16,27 @@ func (receiver *Unique) Signature() string {
 }
 
 // Passes determines if the validation rule passes.
+// Usage: unique:table,column[,ignoreId]. Empty values pass so the rule can
+// guard optional columns such as users.username, and the row with ignoreId,
+// if given, is not counted. Values are compared as they are: emails and
+// usernames are lowercased by the "lower" filter of their form requests, so
+// the column's index can be used.
 func (receiver *Unique) Passes(data validation.Data, val any, options ...any) bool {
 	var isExists bool
 	var tableName = options[0].(string)
 	var columnName = options[1].(string)
-	err := facades.Orm().Query().Table(tableName).Where(columnName, val).Exists(&isExists)
+
+	if str, ok := val.(string); ok && str == "" {
+		return true
+	}
+
+	query := facades.Orm().Query().Table(tableName).Where(columnName, val)
+
+	if len(options) > 2 && fmt.Sprint(options[2]) != "" {
+		query = query.Where("id <> ?", options[2])
+	}
+
+	err := query.Exists(&isExists)
 	if err != nil {
 		return true
 	}
@@ -1,3 +1,25 @@
 package breeze
 
-type Breeze struct{}
+import (
+	"fmt"
+
+	"github.com/goravel/framework/contracts/foundation"
+	"github.com/samehelhawary/goravel-breeze/contracts"
+)
+
+type Breeze struct {
+	app foundation.Application
+}
+
+func (b *Breeze) Encrypter() (contracts.Encrypter, error) {
+	instance, err := b.app.Make(EncrypterBinding)
+	if err != nil {
+		return nil, err
+	}
+	encrypter, ok := instance.(contracts.Encrypter)
+	if !ok {
+		return nil, fmt.Errorf("%s is bound to %T, not an encrypter", EncrypterBinding, instance)
+	}
+
+	return encrypter, nil
+}
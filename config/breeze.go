@@ -4,5 +4,133 @@ import "github.com/goravel/framework/facades"
 
 func init() {
 	config := facades.Config()
-	config.Add("breeze", map[string]any{})
+	config.Add("breeze", map[string]any{
+		// Encryption Keys
+		//
+		// Cookies are encrypted with "key", which defaults to APP_KEY. Keys are
+		// either 32 characters long or "base64:" followed by 32 base64 encoded
+		// bytes, as generated by the breeze:key command. To rotate the key
+		// without logging everyone out, list former keys (comma separated) in
+		// "previous_keys": they are still accepted and cookies using them are
+		// re-encrypted with the current key. Their lifetime is unknown, so they
+		// are sent back as browser session cookies unless the application queues
+		// them again, see middleware.IsStaleCookie.
+		//
+		// The "laravel" format emits and reads Laravel's encrypted cookie payload
+		// and prefix, so cookies can be shared with a Laravel application using
+		// the same key. Its cipher is either "aes-256-cbc" or "aes-256-gcm".
+		"encryption": map[string]any{
+			"key":           config.Env("BREEZE_KEY", config.Env("APP_KEY", "")),
+			"previous_keys": config.Env("APP_PREVIOUS_KEYS", ""),
+			"format":        config.Env("COOKIE_ENCRYPTION_FORMAT", "breeze"),
+			"cipher":        config.Env("COOKIE_ENCRYPTION_CIPHER", "aes-256-cbc"),
+		},
+
+		// Cookies
+		//
+		// Cookies are encrypted by default. Cookies listed in "signed" stay
+		// readable, e.g. by frontend JavaScript, but are signed so they cannot be
+		// tampered with, and cookies listed in "plain" are left untouched.
+		//
+		// With the "laravel" format, cookies listed in "serialize" are PHP
+		// serialized before encryption, like values encrypted with Laravel's
+		// encrypt() helper. Other formats ignore the list and log a warning.
+		// Go values such as maps and structs are stored with
+		// cookies.Cookie[T], which JSON encodes them, whatever the format.
+		"cookies": map[string]any{
+			"signed":    []string{},
+			"plain":     []string{},
+			"serialize": []string{},
+		},
+
+		// Passkeys (WebAuthn)
+		//
+		// These options describe the relying party used by the passkey
+		// registration and login ceremonies. The RP ID is usually the bare
+		// domain of the application and the origins are the fully qualified
+		// URLs (comma separated) that browsers are allowed to sign for.
+		//
+		// When "second_factor" is enabled, users that have registered a passkey
+		// must confirm it after signing in with their password.
+		"webauthn": map[string]any{
+			"rp_id":           config.Env("WEBAUTHN_RP_ID", "localhost"),
+			"rp_display_name": config.Env("WEBAUTHN_RP_DISPLAY_NAME", config.GetString("app.name", "Goravel")),
+			"rp_origins":      config.Env("WEBAUTHN_RP_ORIGINS", config.Env("APP_URL", "http://localhost")),
+			"second_factor":   config.Env("WEBAUTHN_SECOND_FACTOR", false),
+		},
+
+		// Impersonation
+		//
+		// Users whose email is listed here (comma separated) may sign in as
+		// another user through the "impersonate" gate. Administrators can never
+		// be impersonated themselves. The "invite" gate lets the same users
+		// issue registration invitations.
+		"impersonation": map[string]any{
+			"admins": config.Env("IMPERSONATION_ADMINS", ""),
+		},
+
+		// Login Identifier
+		//
+		// Users may sign in with their "email", their "username" or "either".
+		// When "either" is used, input containing an "@" is treated as an email
+		// address and anything else as a username.
+		"login": map[string]any{
+			"identifier": config.Env("LOGIN_IDENTIFIER", "email"),
+		},
+
+		// Email Changes
+		//
+		// A new email address is only applied once it has been confirmed through
+		// a signed link sent to it. The link expires after the given minutes.
+		"email_change": map[string]any{
+			"link_lifetime": config.Env("EMAIL_CHANGE_LINK_LIFETIME", 60),
+		},
+
+		// Registration
+		//
+		// This option controls who may create an account. Supported modes are
+		// "open", "closed" and "invite-only". In invite-only mode a user needs
+		// an invitation link issued by an administrator (or by the breeze:invite
+		// command) and can only register the email the invitation was sent to.
+		//
+		// Invitations expire after the given number of minutes.
+		"registration": map[string]any{
+			"mode":                config.Env("REGISTRATION_MODE", "open"),
+			"invitation_lifetime": config.Env("REGISTRATION_INVITATION_LIFETIME", 10080),
+		},
+
+		// CSRF Protection
+		//
+		// The "store" option controls where CSRF tokens are kept. The "session"
+		// store ties the token to the session, so it expires with it and is
+		// discarded on logout. The "cache" store keeps tokens in the default
+		// cache, keyed by session ID, for the session's lifetime.
+		//
+		// Requests matching a pattern in "except" skip CSRF verification, which
+		// is useful for webhooks. Patterns containing "://" are matched against
+		// the full URL, those containing "/" against the path and anything else
+		// against a name given with middleware.RouteName. "*" is a wildcard.
+		//
+		// When "check_origin" is enabled, unsafe requests whose Sec-Fetch-Site,
+		// Origin or Referer headers point to a foreign origin are rejected before
+		// the token is checked. Token-less API calls are accepted from these
+		// origins with middleware.NewCSRFVerifier().OriginOnly(). The
+		// application URL is always trusted, and further origins (comma
+		// separated, "*" wildcards allowed) may be trusted too. Origins match
+		// on scheme, host and port, so "https://example.com" does not trust
+		// "http://example.com" or "https://example.com:8443".
+		//
+		// Tokens are refreshed while the session is active and can be fetched
+		// again from /csrf-token. Enabling "refresh_script" makes csrf_meta()
+		// include a small script that keeps forms on long-lived tabs current.
+		"csrf": map[string]any{
+			"check_origin":    config.Env("CSRF_CHECK_ORIGIN", false),
+			"trusted_origins": config.Env("CSRF_TRUSTED_ORIGINS", ""),
+			"store":           config.Env("CSRF_STORE", "session"),
+			"refresh_script":  config.Env("CSRF_REFRESH_SCRIPT", false),
+			"except":          []string{
+				// "/webhooks/*",
+			},
+		},
+	})
 }
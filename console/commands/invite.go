@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"fmt"
+	"net/mail"
+
+	"github.com/goravel/framework/contracts/console"
+	"github.com/goravel/framework/contracts/console/command"
+	"github.com/samehelhawary/goravel-breeze/app/invitations"
+)
+
+type Invite struct {
+}
+
+func (receiver *Invite) Extend() command.Extend {
+	return command.Extend{
+		ArgsUsage: " <email>",
+	}
+}
+
+// Signature the name and signature of the console command.
+func (receiver *Invite) Signature() string {
+	return "breeze:invite"
+}
+
+// Description the console command description.
+func (receiver *Invite) Description() string {
+	return "Issue a registration invitation for an email address"
+}
+
+// Handle Execute the console command.
+func (receiver *Invite) Handle(ctx console.Context) error {
+	email := ctx.Argument(0)
+	if _, err := mail.ParseAddress(email); err != nil {
+		ctx.Error(fmt.Sprintf("A valid email address is required: %v", err))
+		return err
+	}
+
+	token, err := invitations.Issue(email, nil)
+	if err != nil {
+		ctx.Error(fmt.Sprintf("Error issuing invitation: %v", err))
+		return err
+	}
+
+	if invitations.Mode() != invitations.ModeInviteOnly {
+		ctx.Warning("Registration is not in invite-only mode, the invitation is not required to register.")
+	}
+	ctx.Info(fmt.Sprintf("Invitation issued for %s:", email))
+	ctx.Line(invitations.URL(token))
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/goravel/framework/contracts/console"
+	"github.com/goravel/framework/contracts/console/command"
+	"github.com/samehelhawary/goravel-breeze/crypt"
+)
+
+type Key struct {
+}
+
+func (receiver *Key) Extend() command.Extend {
+	return command.Extend{
+		Flags: []command.Flag{
+			&command.BoolFlag{
+				Name:  "show",
+				Usage: "Display the key instead of modifying the .env file",
+			},
+			&command.BoolFlag{
+				Name:  "rotate",
+				Usage: "Keep the current key in APP_PREVIOUS_KEYS so existing cookies stay valid",
+			},
+			&command.BoolFlag{
+				Name:  "force",
+				Usage: "Replace the key in use (BREEZE_KEY or APP_KEY) without keeping it",
+			},
+		},
+	}
+}
+
+// Signature the name and signature of the console command.
+func (receiver *Key) Signature() string {
+	return "breeze:key"
+}
+
+// Description the console command description.
+func (receiver *Key) Description() string {
+	return "Generate the cookie encryption key (BREEZE_KEY)"
+}
+
+// Handle Execute the console command.
+func (receiver *Key) Handle(ctx console.Context) error {
+	key, err := crypt.GenerateKey()
+	if err != nil {
+		ctx.Error(fmt.Sprintf("Error generating key: %v", err))
+		return err
+	}
+
+	if ctx.OptionBool("show") {
+		ctx.Line(key)
+		return nil
+	}
+
+	content, err := os.ReadFile(".env")
+	if err != nil {
+		ctx.Error(fmt.Sprintf("Error reading .env file: %v", err))
+		return err
+	}
+	env := string(content)
+
+	// Cookies are encrypted with APP_KEY until a BREEZE_KEY is set
+	name, current := "BREEZE_KEY", receiver.get(env, "BREEZE_KEY")
+	if current == "" {
+		name, current = "APP_KEY", receiver.get(env, "APP_KEY")
+	}
+	switch {
+	case ctx.OptionBool("rotate"):
+		if current != "" {
+			previous := current
+			if keys := receiver.get(env, "APP_PREVIOUS_KEYS"); keys != "" {
+				previous += "," + keys
+			}
+			env = receiver.set(env, "APP_PREVIOUS_KEYS", previous)
+		}
+	case receiver.valid(current) && !ctx.OptionBool("force"):
+		ctx.Warning(fmt.Sprintf("Cookies are encrypted with %s, replacing it logs everyone out. Use --rotate to keep existing cookies valid, or --force to replace it.", name))
+		return nil
+	}
+
+	env = receiver.set(env, "BREEZE_KEY", key)
+	if err := os.WriteFile(".env", []byte(env), 0644); err != nil {
+		ctx.Error(fmt.Sprintf("Error writing .env file: %v", err))
+		return err
+	}
+
+	ctx.Info("Cookie encryption key set successfully.")
+
+	return nil
+}
+
+// valid reports whether key can encrypt cookies. An invalid key encrypts
+// nothing, so replacing it logs nobody out.
+func (receiver *Key) valid(key string) bool {
+	_, err := crypt.ParseKey(key)
+	return err == nil
+}
+
+func (receiver *Key) get(env, name string) string {
+	match := regexp.MustCompile(`(?m)^` + name + `=(.*)$`).FindStringSubmatch(env)
+	if match == nil {
+		return ""
+	}
+
+	return strings.Trim(strings.TrimSpace(match[1]), `"`)
+}
+
+func (receiver *Key) set(env, name, value string) string {
+	line := name + "=" + value
+	pattern := regexp.MustCompile(`(?m)^` + name + `=.*$`)
+	if pattern.MatchString(env) {
+		return pattern.ReplaceAllLiteralString(env, line)
+	}
+	if env != "" && !strings.HasSuffix(env, "\n") {
+		env += "\n"
+	}
+
+	return env + line + "\n"
+}
@@ -0,0 +1,130 @@
+package commands
+
+import (
+	"os"
+	"testing"
+
+	mocksconsole "github.com/goravel/framework/mocks/console"
+	"github.com/samehelhawary/goravel-breeze/crypt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyGeneratesAnAesKey(t *testing.T) {
+	ctx := mocksconsole.NewContext(t)
+	ctx.EXPECT().OptionBool("show").Return(true).Once()
+	var key string
+	ctx.EXPECT().Line(mock.Anything).Run(func(message string) { key = message }).Once()
+
+	require.NoError(t, (&Key{}).Handle(ctx))
+
+	assert.Regexp(t, `^base64:[A-Za-z0-9+/]{43}=$`, key)
+	assertRoundTrips(t, key)
+}
+
+func TestKeyRotatesTheKeyInEnv(t *testing.T) {
+	t.Chdir(t.TempDir())
+	const appKey = "breeze-testkit-app-key-32-bytes!"
+	require.NoError(t, os.WriteFile(".env", []byte("APP_NAME=Breeze\nAPP_KEY="+appKey+"\n"), 0644))
+
+	old, err := crypt.NewAesEncrypter(appKey)
+	require.NoError(t, err)
+	payload, err := old.EncryptString("remembered")
+	require.NoError(t, err)
+
+	ctx := mocksconsole.NewContext(t)
+	ctx.EXPECT().OptionBool("show").Return(false).Once()
+	ctx.EXPECT().OptionBool("rotate").Return(true).Once()
+	ctx.EXPECT().Info(mock.Anything).Once()
+
+	require.NoError(t, (&Key{}).Handle(ctx))
+
+	content, err := os.ReadFile(".env")
+	require.NoError(t, err)
+	env := string(content)
+	key := (&Key{}).get(env, "BREEZE_KEY")
+	assert.Equal(t, appKey, (&Key{}).get(env, "APP_PREVIOUS_KEYS"))
+	assert.Equal(t, appKey, (&Key{}).get(env, "APP_KEY"))
+	assertRoundTrips(t, key)
+
+	rotated, err := crypt.NewAesEncrypter(key, appKey)
+	require.NoError(t, err)
+	value, err := rotated.DecryptString(payload)
+	require.NoError(t, err)
+	assert.Equal(t, "remembered", value)
+}
+
+func TestKeyKeepsTheKeyInUseUnlessForced(t *testing.T) {
+	const appKey = "breeze-testkit-app-key-32-bytes!"
+	tests := []struct {
+		name string
+		env  string
+		used string
+	}{
+		{"app key", "APP_KEY=" + appKey + "\n", "APP_KEY"},
+		{"breeze key", "APP_KEY=" + appKey + "\nBREEZE_KEY=" + appKey + "\n", "BREEZE_KEY"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			require.NoError(t, os.WriteFile(".env", []byte(test.env), 0644))
+
+			ctx := mocksconsole.NewContext(t)
+			ctx.EXPECT().OptionBool("show").Return(false).Once()
+			ctx.EXPECT().OptionBool("rotate").Return(false).Once()
+			ctx.EXPECT().OptionBool("force").Return(false).Once()
+			var warning string
+			ctx.EXPECT().Warning(mock.Anything).Run(func(message string) { warning = message }).Once()
+
+			require.NoError(t, (&Key{}).Handle(ctx))
+			assert.Contains(t, warning, test.used)
+			content, err := os.ReadFile(".env")
+			require.NoError(t, err)
+			assert.Equal(t, test.env, string(content))
+
+			forced := mocksconsole.NewContext(t)
+			forced.EXPECT().OptionBool("show").Return(false).Once()
+			forced.EXPECT().OptionBool("rotate").Return(false).Once()
+			forced.EXPECT().OptionBool("force").Return(true).Once()
+			forced.EXPECT().Info(mock.Anything).Once()
+
+			require.NoError(t, (&Key{}).Handle(forced))
+			content, err = os.ReadFile(".env")
+			require.NoError(t, err)
+			key := (&Key{}).get(string(content), "BREEZE_KEY")
+			assert.NotEqual(t, appKey, key)
+			assert.Empty(t, (&Key{}).get(string(content), "APP_PREVIOUS_KEYS"))
+			assertRoundTrips(t, key)
+		})
+	}
+}
+
+func TestKeyReplacesAnInvalidAppKey(t *testing.T) {
+	t.Chdir(t.TempDir())
+	require.NoError(t, os.WriteFile(".env", []byte("APP_KEY=too-short\n"), 0644))
+
+	ctx := mocksconsole.NewContext(t)
+	ctx.EXPECT().OptionBool("show").Return(false).Once()
+	ctx.EXPECT().OptionBool("rotate").Return(false).Once()
+	ctx.EXPECT().Info(mock.Anything).Once()
+
+	require.NoError(t, (&Key{}).Handle(ctx))
+	content, err := os.ReadFile(".env")
+	require.NoError(t, err)
+	assertRoundTrips(t, (&Key{}).get(string(content), "BREEZE_KEY"))
+}
+
+func assertRoundTrips(t *testing.T, key string) {
+	t.Helper()
+
+	encrypter, err := crypt.NewAesEncrypter(key)
+	require.NoError(t, err)
+
+	payload, err := encrypter.EncryptString("secret")
+	require.NoError(t, err)
+	value, err := encrypter.DecryptString(payload)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", value)
+}
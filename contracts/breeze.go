@@ -1,3 +1,7 @@
 package contracts
 
-type Breeze interface{}
+type Breeze interface {
+	// Encrypter returns the shared encrypter, or an error when the configured
+	// key is invalid.
+	Encrypter() (Encrypter, error)
+}
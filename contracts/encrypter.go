@@ -0,0 +1,26 @@
+package contracts
+
+// Encrypter encrypts and decrypts values with the Breeze encryption key,
+// accepting values encrypted with any of the previous keys. Values are
+// encrypted as they are; Go values are serialized with crypt.Encrypt and
+// crypt.Decrypt, or cookies.Cookie for cookies.
+type Encrypter interface {
+	// EncryptString encrypts a string.
+	EncryptString(value string) (string, error)
+	// DecryptString decrypts a payload made by EncryptString.
+	DecryptString(payload string) (string, error)
+	// GetKey returns the current key.
+	GetKey() string
+	// GetAllKeys returns the current key followed by the previous keys.
+	GetAllKeys() []string
+}
+
+// PHPSerializer is implemented by encrypters that can PHP serialize values
+// before encrypting them, as Laravel's encrypt() helper does. EncryptCookies
+// uses it for the cookies listed in breeze.cookies.serialize.
+type PHPSerializer interface {
+	// EncryptSerialized PHP serializes a string and encrypts it.
+	EncryptSerialized(value string) (string, error)
+	// DecryptSerialized decrypts a payload and PHP unserializes the string.
+	DecryptSerialized(payload string) (string, error)
+}
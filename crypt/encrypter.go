@@ -0,0 +1,235 @@
+// Package crypt encrypts values with the Breeze encryption key. The shared
+// encrypter is bound in the container and resolved through
+// facades.Breeze().Encrypter(), so cookies, remember tokens and signed URLs
+// follow the same key rotation.
+package crypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/goravel/framework/facades"
+	"github.com/samehelhawary/goravel-breeze/contracts"
+)
+
+// Failure is the reason a payload could not be decrypted.
+type Failure string
+
+const (
+	FailureBadBase64        Failure = "bad_base64"
+	FailureBadPayload       Failure = "bad_payload"
+	FailureBadTag           Failure = "bad_tag"
+	FailureBadSerialization Failure = "bad_serialization"
+)
+
+// NewFromConfig creates the encrypter configured by breeze.encryption: the
+// key, the previous keys and the payload format.
+func NewFromConfig() (contracts.Encrypter, error) {
+	var previousKeys []string
+	for _, key := range strings.Split(facades.Config().GetString("breeze.encryption.previous_keys"), ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			previousKeys = append(previousKeys, key)
+		}
+	}
+	key := facades.Config().GetString("breeze.encryption.key")
+	var (
+		encrypter contracts.Encrypter
+		err       error
+	)
+	if facades.Config().GetString("breeze.encryption.format") == "laravel" {
+		encrypter, err = NewLaravelEncrypter(facades.Config().GetString("breeze.encryption.cipher", "aes-256-cbc"), key, previousKeys...)
+	} else {
+		encrypter, err = NewAesEncrypter(key, previousKeys...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return encrypter, nil
+}
+
+// Encrypt JSON encodes value and encrypts it.
+func Encrypt[T any](encrypter contracts.Encrypter, value T) (string, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return encrypter.EncryptString(string(encoded))
+}
+
+// Decrypt decrypts a payload made by Encrypt and JSON decodes it into a T.
+func Decrypt[T any](encrypter contracts.Encrypter, payload string) (T, error) {
+	var value T
+	decrypted, err := encrypter.DecryptString(payload)
+	if err != nil {
+		return value, err
+	}
+	if err := json.Unmarshal([]byte(decrypted), &value); err != nil {
+		return value, &DecryptException{Reason: FailureBadSerialization, Err: err}
+	}
+	return value, nil
+}
+
+// DecryptException represents a failure during the decryption process.
+type DecryptException struct {
+	Reason Failure
+	Err    error
+}
+
+func (e *DecryptException) Error() string {
+	return fmt.Sprintf("decryption failed: %v", e.Err)
+}
+
+// AesEncrypter provides an AES-256 GCM implementation of contracts.Encrypter.
+// Values are encrypted with the current key and decrypted with any of the
+// current or previous keys, so keys can be rotated without downtime.
+type AesEncrypter struct {
+	// keys holds the current key followed by the previous keys.
+	keys []aesKey
+}
+
+// aesKey is an encryption key with its cipher instances, which are created
+// once as they are safe for concurrent use.
+type aesKey struct {
+	raw   []byte
+	block cipher.Block
+	gcm   cipher.AEAD
+}
+
+func newAesKey(raw []byte) (aesKey, error) {
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return aesKey{}, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return aesKey{}, err
+	}
+	return aesKey{raw: raw, block: block, gcm: gcm}, nil
+}
+
+// parseKeys parses the current and previous keys.
+func parseKeys(key string, previousKeys []string) ([]aesKey, error) {
+	parsed, err := ParseKey(key)
+	if err != nil {
+		return nil, err
+	}
+	current, err := newAesKey(parsed)
+	if err != nil {
+		return nil, err
+	}
+	keys := []aesKey{current}
+	for _, previousKey := range previousKeys {
+		parsed, err := ParseKey(previousKey)
+		if err != nil {
+			return nil, fmt.Errorf("previous key: %w", err)
+		}
+		previous, err := newAesKey(parsed)
+		if err != nil {
+			return nil, fmt.Errorf("previous key: %w", err)
+		}
+		keys = append(keys, previous)
+	}
+	return keys, nil
+}
+
+// rawKeys returns the keys as strings, current key first.
+func rawKeys(keys []aesKey) []string {
+	raw := make([]string, len(keys))
+	for i, key := range keys {
+		raw[i] = string(key.raw)
+	}
+	return raw
+}
+
+// NewAesEncrypter creates a new AES encrypter. Keys are either raw 32 byte
+// strings or "base64:" followed by 32 base64 encoded bytes.
+func NewAesEncrypter(key string, previousKeys ...string) (*AesEncrypter, error) {
+	keys, err := parseKeys(key, previousKeys)
+	if err != nil {
+		return nil, err
+	}
+	return &AesEncrypter{keys: keys}, nil
+}
+
+// ParseKey decodes an encryption key, which is either a raw 32 byte string or
+// "base64:" followed by 32 base64 encoded bytes.
+func ParseKey(key string) ([]byte, error) {
+	if encoded, ok := strings.CutPrefix(key, "base64:"); ok {
+		decoded, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("encryption key is not valid base64: %w", err)
+		}
+		if len(decoded) != 32 {
+			return nil, fmt.Errorf("encryption key must decode to 32 bytes for AES-256, got %d", len(decoded))
+		}
+		return decoded, nil
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("encryption key must be 32 bytes for AES-256, got %d", len(key))
+	}
+	return []byte(key), nil
+}
+
+// GenerateKey returns a random "base64:" encoded 32 byte key.
+func GenerateKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return "", err
+	}
+	return "base64:" + base64.StdEncoding.EncodeToString(key), nil
+}
+
+// EncryptString encrypts a string using AES-256 GCM.
+func (e *AesEncrypter) EncryptString(value string) (string, error) {
+	aesGCM := e.keys[0].gcm
+	nonce := make([]byte, aesGCM.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	ciphertext := aesGCM.Seal(nonce, nonce, []byte(value), nil)
+	return base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// DecryptString decrypts a string using AES-256 GCM, trying the current key
+// first and then each previous key.
+func (e *AesEncrypter) DecryptString(payload string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return "", &DecryptException{Reason: FailureBadBase64, Err: err}
+	}
+	var plaintext string
+	for _, key := range e.keys {
+		if plaintext, err = e.decryptWith(key.gcm, data); err == nil {
+			break
+		}
+	}
+	return plaintext, err
+}
+
+func (e *AesEncrypter) decryptWith(aesGCM cipher.AEAD, data []byte) (string, error) {
+	nonceSize := aesGCM.NonceSize()
+	if len(data) < nonceSize {
+		return "", &DecryptException{Reason: FailureBadPayload, Err: errors.New("ciphertext is too short")}
+	}
+	nonce, ciphertext := data[:nonceSize], data[nonceSize:]
+	plaintext, err := aesGCM.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", &DecryptException{Reason: FailureBadTag, Err: err}
+	}
+	return string(plaintext), nil
+}
+
+func (e *AesEncrypter) GetKey() string {
+	return string(e.keys[0].raw)
+}
+
+func (e *AesEncrypter) GetAllKeys() []string {
+	return rawKeys(e.keys)
+}
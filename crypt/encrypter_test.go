@@ -0,0 +1,100 @@
+package crypt
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/samehelhawary/goravel-breeze/contracts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const previousKey = "breeze-previous-key-of-32-bytes!"
+
+type settings struct {
+	Theme string   `json:"theme"`
+	Tags  []string `json:"tags"`
+}
+
+func TestEncryptAndDecryptValues(t *testing.T) {
+	aes, err := NewAesEncrypter(testKey)
+	require.NoError(t, err)
+	laravel, err := NewLaravelEncrypter("aes-256-cbc", testKey)
+	require.NoError(t, err)
+
+	for name, encrypter := range map[string]contracts.Encrypter{"breeze": aes, "laravel": laravel} {
+		t.Run(name, func(t *testing.T) {
+			want := settings{Theme: "dark", Tags: []string{"a", "b"}}
+			payload, err := Encrypt(encrypter, want)
+			require.NoError(t, err)
+			got, err := Decrypt[settings](encrypter, payload)
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+
+			payload, err = Encrypt(encrypter, 42)
+			require.NoError(t, err)
+			number, err := Decrypt[int](encrypter, payload)
+			require.NoError(t, err)
+			assert.Equal(t, 42, number)
+
+			_, err = Decrypt[int](encrypter, mustEncryptString(t, encrypter, `"dark"`))
+			var exception *DecryptException
+			require.True(t, errors.As(err, &exception))
+			assert.Equal(t, FailureBadSerialization, exception.Reason)
+		})
+	}
+}
+
+func TestDecryptValuesOfPreviousKeys(t *testing.T) {
+	previous, err := NewAesEncrypter(previousKey)
+	require.NoError(t, err)
+	payload, err := Encrypt(previous, settings{Theme: "dark"})
+	require.NoError(t, err)
+
+	rotated, err := NewAesEncrypter(testKey, previousKey)
+	require.NoError(t, err)
+	got, err := Decrypt[settings](rotated, payload)
+	require.NoError(t, err)
+	assert.Equal(t, "dark", got.Theme)
+
+	current, err := NewAesEncrypter(testKey)
+	require.NoError(t, err)
+	_, err = Decrypt[settings](current, payload)
+	var exception *DecryptException
+	require.True(t, errors.As(err, &exception))
+	assert.Equal(t, FailureBadTag, exception.Reason)
+}
+
+func TestDecryptRejectsTamperedPayloads(t *testing.T) {
+	encrypter, err := NewAesEncrypter(testKey)
+	require.NoError(t, err)
+	payload, err := Encrypt(encrypter, "value")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		payload string
+		reason  Failure
+	}{
+		{"not base64", "not base64!", FailureBadBase64},
+		{"too short", "AAAA", FailureBadPayload},
+		{"tampered", payload[:len(payload)-4] + "AAA=", FailureBadTag},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Decrypt[string](encrypter, test.payload)
+			var exception *DecryptException
+			require.True(t, errors.As(err, &exception), err)
+			assert.Equal(t, test.reason, exception.Reason)
+		})
+	}
+}
+
+func mustEncryptString(t *testing.T, encrypter contracts.Encrypter, value string) string {
+	t.Helper()
+
+	payload, err := encrypter.EncryptString(value)
+	require.NoError(t, err)
+	return payload
+}
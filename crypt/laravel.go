@@ -0,0 +1,226 @@
+package crypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// laravelPayload is the JSON envelope produced by Laravel's Encrypter.
+type laravelPayload struct {
+	IV    string `json:"iv"`
+	Value string `json:"value"`
+	MAC   string `json:"mac"`
+	Tag   string `json:"tag"`
+}
+
+// LaravelEncrypter implements contracts.Encrypter with Laravel's payload
+// format, so cookies can be shared with a Laravel application using the same
+// key. Both "aes-256-cbc" (with an HMAC-SHA256 MAC) and "aes-256-gcm" are
+// supported.
+type LaravelEncrypter struct {
+	cipher string
+	// keys holds the current key followed by the previous keys.
+	keys []aesKey
+}
+
+// NewLaravelEncrypter creates a Laravel compatible encrypter. Keys use the
+// same formats as NewAesEncrypter.
+func NewLaravelEncrypter(cipherName, key string, previousKeys ...string) (*LaravelEncrypter, error) {
+	cipherName = strings.ToLower(cipherName)
+	if cipherName != "aes-256-cbc" && cipherName != "aes-256-gcm" {
+		return nil, fmt.Errorf("unsupported cipher %q, use aes-256-cbc or aes-256-gcm", cipherName)
+	}
+	keys, err := parseKeys(key, previousKeys)
+	if err != nil {
+		return nil, err
+	}
+	return &LaravelEncrypter{cipher: cipherName, keys: keys}, nil
+}
+
+// EncryptString encrypts a string into a base64 encoded Laravel payload, as
+// Laravel's encryptString() does.
+func (e *LaravelEncrypter) EncryptString(value string) (string, error) {
+	key := e.keys[0]
+
+	var payload laravelPayload
+	if e.cipher == "aes-256-gcm" {
+		aesGCM := key.gcm
+		iv := make([]byte, aesGCM.NonceSize())
+		if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+			return "", err
+		}
+		sealed := aesGCM.Seal(nil, iv, []byte(value), nil)
+		ciphertext, tag := sealed[:len(sealed)-aesGCM.Overhead()], sealed[len(sealed)-aesGCM.Overhead():]
+		payload = laravelPayload{
+			IV:    base64.StdEncoding.EncodeToString(iv),
+			Value: base64.StdEncoding.EncodeToString(ciphertext),
+			Tag:   base64.StdEncoding.EncodeToString(tag),
+		}
+	} else {
+		iv := make([]byte, aes.BlockSize)
+		if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+			return "", err
+		}
+		plaintext := pkcs7Pad([]byte(value), aes.BlockSize)
+		ciphertext := make([]byte, len(plaintext))
+		cipher.NewCBCEncrypter(key.block, iv).CryptBlocks(ciphertext, plaintext)
+		payload = laravelPayload{
+			IV:    base64.StdEncoding.EncodeToString(iv),
+			Value: base64.StdEncoding.EncodeToString(ciphertext),
+		}
+		payload.MAC = laravelMAC(key.raw, payload.IV, payload.Value)
+	}
+
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(encoded), nil
+}
+
+// DecryptString decrypts a Laravel payload, trying the current key first and
+// then each previous key.
+func (e *LaravelEncrypter) DecryptString(payload string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return "", &DecryptException{Reason: FailureBadBase64, Err: err}
+	}
+	var decoded laravelPayload
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return "", &DecryptException{Reason: FailureBadPayload, Err: fmt.Errorf("invalid payload: %w", err)}
+	}
+	var plaintext string
+	for _, key := range e.keys {
+		if plaintext, err = e.decryptWith(key, decoded); err == nil {
+			break
+		}
+	}
+	return plaintext, err
+}
+
+// EncryptSerialized PHP serializes a string and encrypts it, as Laravel's
+// encrypt() helper does.
+func (e *LaravelEncrypter) EncryptSerialized(value string) (string, error) {
+	return e.EncryptString(phpSerializeString(value))
+}
+
+// DecryptSerialized decrypts a payload made by Laravel's encrypt() helper,
+// which must hold a PHP serialized string.
+func (e *LaravelEncrypter) DecryptSerialized(payload string) (string, error) {
+	plaintext, err := e.DecryptString(payload)
+	if err != nil {
+		return "", err
+	}
+	value, err := phpUnserializeString(plaintext)
+	if err != nil {
+		return "", &DecryptException{Reason: FailureBadSerialization, Err: err}
+	}
+	return value, nil
+}
+
+func (e *LaravelEncrypter) decryptWith(key aesKey, payload laravelPayload) (string, error) {
+	iv, err := base64.StdEncoding.DecodeString(payload.IV)
+	if err != nil {
+		return "", &DecryptException{Reason: FailureBadBase64, Err: err}
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(payload.Value)
+	if err != nil {
+		return "", &DecryptException{Reason: FailureBadBase64, Err: err}
+	}
+	if e.cipher == "aes-256-gcm" {
+		tag, err := base64.StdEncoding.DecodeString(payload.Tag)
+		if err != nil {
+			return "", &DecryptException{Reason: FailureBadBase64, Err: err}
+		}
+		aesGCM := key.gcm
+		if len(iv) != aesGCM.NonceSize() || len(tag) != aesGCM.Overhead() {
+			return "", &DecryptException{Reason: FailureBadPayload, Err: errors.New("invalid iv or tag length")}
+		}
+		plaintext, err := aesGCM.Open(nil, iv, append(ciphertext, tag...), nil)
+		if err != nil {
+			return "", &DecryptException{Reason: FailureBadTag, Err: err}
+		}
+		return string(plaintext), nil
+	}
+
+	if !hmac.Equal([]byte(payload.MAC), []byte(laravelMAC(key.raw, payload.IV, payload.Value))) {
+		return "", &DecryptException{Reason: FailureBadTag, Err: errors.New("the MAC is invalid")}
+	}
+	if len(iv) != aes.BlockSize || len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		return "", &DecryptException{Reason: FailureBadPayload, Err: errors.New("invalid iv or ciphertext length")}
+	}
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(key.block, iv).CryptBlocks(plaintext, ciphertext)
+	plaintext, err = pkcs7Unpad(plaintext, aes.BlockSize)
+	if err != nil {
+		return "", &DecryptException{Reason: FailureBadPayload, Err: err}
+	}
+	return string(plaintext), nil
+}
+
+func (e *LaravelEncrypter) GetKey() string {
+	return string(e.keys[0].raw)
+}
+
+func (e *LaravelEncrypter) GetAllKeys() []string {
+	return rawKeys(e.keys)
+}
+
+// laravelMAC computes the MAC Laravel attaches to CBC payloads, a hex encoded
+// HMAC-SHA256 of the base64 encoded IV and value.
+func laravelMAC(key []byte, iv, value string) string {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(iv + value))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// phpSerializeString encodes a string the way PHP's serialize() does.
+func phpSerializeString(value string) string {
+	return fmt.Sprintf("s:%d:\"%s\";", len(value), value)
+}
+
+// phpUnserializeString decodes a string serialized by PHP's serialize().
+func phpUnserializeString(serialized string) (string, error) {
+	rest, ok := strings.CutPrefix(serialized, "s:")
+	if !ok {
+		return "", errors.New("serialized value is not a string")
+	}
+	length, rest, ok := strings.Cut(rest, ":")
+	size, err := strconv.Atoi(length)
+	if !ok || err != nil || size < 0 || len(rest) != size+3 || rest[0] != '"' || rest[size+1:] != "\";" {
+		return "", errors.New("malformed serialized string")
+	}
+	return rest[1 : size+1], nil
+}
+
+func pkcs7Pad(data []byte, blockSize int) []byte {
+	padding := blockSize - len(data)%blockSize
+	for i := 0; i < padding; i++ {
+		data = append(data, byte(padding))
+	}
+	return data
+}
+
+func pkcs7Unpad(data []byte, blockSize int) ([]byte, error) {
+	padding := int(data[len(data)-1])
+	if padding == 0 || padding > blockSize || padding > len(data) {
+		return nil, errors.New("invalid padding")
+	}
+	for _, b := range data[len(data)-padding:] {
+		if int(b) != padding {
+			return nil, errors.New("invalid padding")
+		}
+	}
+	return data[:len(data)-padding], nil
+}
@@ -0,0 +1,58 @@
+package crypt
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/samehelhawary/goravel-breeze/contracts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testKey = "breeze-testkit-app-key-32-bytes!"
+
+func TestLaravelEncrypterSerializesOnlyOnRequest(t *testing.T) {
+	for _, cipher := range []string{"aes-256-cbc", "aes-256-gcm"} {
+		t.Run(cipher, func(t *testing.T) {
+			encrypter, err := NewLaravelEncrypter(cipher, testKey)
+			require.NoError(t, err)
+
+			payload, err := encrypter.EncryptString(`{"theme":"dark"}`)
+			require.NoError(t, err)
+			value, err := encrypter.DecryptString(payload)
+			require.NoError(t, err)
+			assert.Equal(t, `{"theme":"dark"}`, value)
+
+			payload, err = encrypter.EncryptSerialized("dark é")
+			require.NoError(t, err)
+			value, err = encrypter.DecryptString(payload)
+			require.NoError(t, err)
+			assert.Equal(t, `s:7:"dark é";`, value)
+			value, err = encrypter.DecryptSerialized(payload)
+			require.NoError(t, err)
+			assert.Equal(t, "dark é", value)
+
+			plain, err := encrypter.EncryptString("dark")
+			require.NoError(t, err)
+			_, err = encrypter.DecryptSerialized(plain)
+			var exception *DecryptException
+			require.True(t, errors.As(err, &exception))
+			assert.Equal(t, FailureBadSerialization, exception.Reason)
+		})
+	}
+}
+
+func TestOnlyTheLaravelFormatSerializes(t *testing.T) {
+	aes, err := NewAesEncrypter(testKey)
+	require.NoError(t, err)
+	laravel, err := NewLaravelEncrypter("aes-256-cbc", testKey)
+	require.NoError(t, err)
+
+	var encrypter contracts.Encrypter = aes
+	_, ok := encrypter.(contracts.PHPSerializer)
+	assert.False(t, ok)
+
+	encrypter = laravel
+	_, ok = encrypter.(contracts.PHPSerializer)
+	assert.True(t, ok)
+}
@@ -0,0 +1,46 @@
+package migrations
+
+import (
+	"github.com/goravel/framework/contracts/database/schema"
+	"github.com/goravel/framework/facades"
+)
+
+type M20261018090000CreateWebauthnCredentialsTable struct {
+}
+
+// Signature The unique signature for the migration.
+func (r *M20261018090000CreateWebauthnCredentialsTable) Signature() string {
+	return "20261018090000_create_webauthn_credentials_table"
+}
+
+// Up Run the migrations.
+func (r *M20261018090000CreateWebauthnCredentialsTable) Up() error {
+	if !facades.Schema().HasTable("webauthn_credentials") {
+		return facades.Schema().Create("webauthn_credentials", func(table schema.Blueprint) {
+			table.ID()
+			table.UnsignedBigInteger("user_id")
+			table.Foreign("user_id").References("id").On("users")
+			table.String("name")
+			table.Text("credential_id")
+			table.String("credential_hash", 64)
+			table.Unique("credential_hash")
+			table.Text("public_key")
+			table.String("attestation_type").Nullable()
+			table.String("transports").Nullable()
+			table.String("aaguid").Nullable()
+			table.UnsignedBigInteger("sign_count").Default(0)
+			table.Boolean("clone_warning").Default(false)
+			table.Boolean("backup_eligible").Default(false)
+			table.Boolean("backup_state").Default(false)
+			table.Timestamp("last_used_at").Nullable()
+			table.Timestamps()
+		})
+	}
+
+	return nil
+}
+
+// Down Reverse the migrations.
+func (r *M20261018090000CreateWebauthnCredentialsTable) Down() error {
+	return facades.Schema().DropIfExists("webauthn_credentials")
+}
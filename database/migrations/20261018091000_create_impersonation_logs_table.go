@@ -0,0 +1,39 @@
+package migrations
+
+import (
+	"github.com/goravel/framework/contracts/database/schema"
+	"github.com/goravel/framework/facades"
+)
+
+type M20261018091000CreateImpersonationLogsTable struct {
+}
+
+// Signature The unique signature for the migration.
+func (r *M20261018091000CreateImpersonationLogsTable) Signature() string {
+	return "20261018091000_create_impersonation_logs_table"
+}
+
+// Up Run the migrations.
+func (r *M20261018091000CreateImpersonationLogsTable) Up() error {
+	if !facades.Schema().HasTable("impersonation_logs") {
+		return facades.Schema().Create("impersonation_logs", func(table schema.Blueprint) {
+			table.ID()
+			table.UnsignedBigInteger("impersonator_id")
+			table.Foreign("impersonator_id").References("id").On("users")
+			table.UnsignedBigInteger("user_id")
+			table.Foreign("user_id").References("id").On("users")
+			table.String("action", 20)
+			table.String("ip_address", 45).Nullable()
+			table.Text("user_agent").Nullable()
+			table.Timestamps()
+			table.Index("impersonator_id", "created_at")
+		})
+	}
+
+	return nil
+}
+
+// Down Reverse the migrations.
+func (r *M20261018091000CreateImpersonationLogsTable) Down() error {
+	return facades.Schema().DropIfExists("impersonation_logs")
+}
@@ -0,0 +1,39 @@
+package migrations
+
+import (
+	"github.com/goravel/framework/contracts/database/schema"
+	"github.com/goravel/framework/facades"
+)
+
+type M20261018092000CreateInvitationsTable struct {
+}
+
+// Signature The unique signature for the migration.
+func (r *M20261018092000CreateInvitationsTable) Signature() string {
+	return "20261018092000_create_invitations_table"
+}
+
+// Up Run the migrations.
+func (r *M20261018092000CreateInvitationsTable) Up() error {
+	if !facades.Schema().HasTable("invitations") {
+		return facades.Schema().Create("invitations", func(table schema.Blueprint) {
+			table.ID()
+			table.String("email")
+			table.Index("email")
+			table.String("token", 64)
+			table.Unique("token")
+			table.UnsignedBigInteger("invited_by").Nullable()
+			table.Foreign("invited_by").References("id").On("users")
+			table.Timestamp("expires_at")
+			table.Timestamp("accepted_at").Nullable()
+			table.Timestamps()
+		})
+	}
+
+	return nil
+}
+
+// Down Reverse the migrations.
+func (r *M20261018092000CreateInvitationsTable) Down() error {
+	return facades.Schema().DropIfExists("invitations")
+}
@@ -0,0 +1,38 @@
+package migrations
+
+import (
+	"github.com/goravel/framework/contracts/database/schema"
+	"github.com/goravel/framework/facades"
+)
+
+type M20261018093000AddUsernameToUsersTable struct {
+}
+
+// Signature The unique signature for the migration.
+func (r *M20261018093000AddUsernameToUsersTable) Signature() string {
+	return "20261018093000_add_username_to_users_table"
+}
+
+// Up Run the migrations.
+func (r *M20261018093000AddUsernameToUsersTable) Up() error {
+	if !facades.Schema().HasColumn("users", "username") {
+		return facades.Schema().Table("users", func(table schema.Blueprint) {
+			table.String("username").Nullable()
+			table.Unique("username")
+		})
+	}
+
+	return nil
+}
+
+// Down Reverse the migrations.
+func (r *M20261018093000AddUsernameToUsersTable) Down() error {
+	if facades.Schema().HasColumn("users", "username") {
+		return facades.Schema().Table("users", func(table schema.Blueprint) {
+			table.DropUnique("username")
+			table.DropColumn("username")
+		})
+	}
+
+	return nil
+}
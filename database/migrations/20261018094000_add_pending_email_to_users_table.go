@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"github.com/goravel/framework/contracts/database/schema"
+	"github.com/goravel/framework/facades"
+)
+
+type M20261018094000AddPendingEmailToUsersTable struct {
+}
+
+// Signature The unique signature for the migration.
+func (r *M20261018094000AddPendingEmailToUsersTable) Signature() string {
+	return "20261018094000_add_pending_email_to_users_table"
+}
+
+// Up Run the migrations.
+func (r *M20261018094000AddPendingEmailToUsersTable) Up() error {
+	if !facades.Schema().HasColumn("users", "pending_email") {
+		return facades.Schema().Table("users", func(table schema.Blueprint) {
+			table.String("pending_email").Nullable()
+		})
+	}
+
+	return nil
+}
+
+// Down Reverse the migrations.
+func (r *M20261018094000AddPendingEmailToUsersTable) Down() error {
+	if facades.Schema().HasColumn("users", "pending_email") {
+		return facades.Schema().DropColumns("users", []string{"pending_email"})
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package migrations
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goravel/framework/facades"
+)
+
+type M20261018095000LowercaseUserIdentifiers struct {
+}
+
+// Signature The unique signature for the migration.
+func (r *M20261018095000LowercaseUserIdentifiers) Signature() string {
+	return "20261018095000_lowercase_user_identifiers"
+}
+
+// Up Run the migrations.
+//
+// Emails and usernames are stored in lower case and compared as they are, so
+// rows written before that are converted once. Rows differing only by case
+// would break the unique indexes halfway through, so they are reported first
+// and have to be merged or renamed by hand.
+func (r *M20261018095000LowercaseUserIdentifiers) Up() error {
+	var duplicates []string
+	for _, column := range []string{"email", "username"} {
+		var rows []struct {
+			Identifier string
+			Users      int
+		}
+		query := fmt.Sprintf("SELECT LOWER(%[1]s) AS identifier, COUNT(*) AS users FROM users WHERE %[1]s IS NOT NULL GROUP BY LOWER(%[1]s) HAVING COUNT(*) > 1", column)
+		if err := facades.Orm().Query().Raw(query).Scan(&rows); err != nil {
+			return err
+		}
+		for _, row := range rows {
+			duplicates = append(duplicates, fmt.Sprintf("%s %q (%d users)", column, row.Identifier, row.Users))
+		}
+	}
+	if len(duplicates) > 0 {
+		return fmt.Errorf("users differ only by the case of their identifiers, merge or rename them before migrating: %s", strings.Join(duplicates, ", "))
+	}
+
+	_, err := facades.Orm().Query().Exec("UPDATE users SET email = LOWER(email), username = LOWER(username)")
+
+	return err
+}
+
+// Down Reverse the migrations.
+func (r *M20261018095000LowercaseUserIdentifiers) Down() error {
+	return nil
+}
@@ -1,18 +1,37 @@
 package facades
 
 import (
-	"log"
+	"errors"
+	"fmt"
 
 	breeze "github.com/samehelhawary/goravel-breeze"
 	"github.com/samehelhawary/goravel-breeze/contracts"
 )
 
+// Breeze returns the Breeze instance. It is never nil: when the service
+// provider is not registered, the error surfaces from its methods instead.
 func Breeze() contracts.Breeze {
+	if breeze.App == nil {
+		return unresolved{err: errors.New("breeze: the service provider is not registered")}
+	}
+
 	instance, err := breeze.App.Make(breeze.Binding)
 	if err != nil {
-		log.Println(err)
-		return nil
+		return unresolved{err: fmt.Errorf("breeze: %w", err)}
 	}
+	resolved, ok := instance.(contracts.Breeze)
+	if !ok {
+		return unresolved{err: fmt.Errorf("breeze: %s is bound to %T", breeze.Binding, instance)}
+	}
+
+	return resolved
+}
+
+// unresolved stands in for a Breeze instance that could not be resolved.
+type unresolved struct {
+	err error
+}
 
-	return instance.(contracts.Breeze)
+func (u unresolved) Encrypter() (contracts.Encrypter, error) {
+	return nil, u.err
 }
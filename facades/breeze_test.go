@@ -0,0 +1,90 @@
+package facades
+
+import (
+	"testing"
+
+	breeze "github.com/samehelhawary/goravel-breeze"
+	"github.com/samehelhawary/goravel-breeze/contracts"
+	"github.com/samehelhawary/goravel-breeze/crypt"
+	"github.com/samehelhawary/goravel-breeze/testkit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBreezeReportsAMissingProvider(t *testing.T) {
+	previousApp := breeze.App
+	t.Cleanup(func() {
+		breeze.App = previousApp
+	})
+	breeze.App = nil
+
+	instance := Breeze()
+	require.NotNil(t, instance)
+
+	encrypter, err := instance.Encrypter()
+	assert.Nil(t, encrypter)
+	assert.ErrorContains(t, err, "service provider is not registered")
+}
+
+func TestBreezeResolvesTheSharedEncrypter(t *testing.T) {
+	previousApp := breeze.App
+	t.Cleanup(func() {
+		breeze.App = previousApp
+	})
+	app := testkit.New(t, nil)
+	(&breeze.ServiceProvider{}).Register(app)
+
+	first, err := Breeze().Encrypter()
+	require.NoError(t, err)
+	second, err := Breeze().Encrypter()
+	require.NoError(t, err)
+
+	assert.Same(t, first, second)
+	assert.Equal(t, testkit.Key, first.GetKey())
+}
+
+func TestBreezeReportsAnInvalidKey(t *testing.T) {
+	previousApp := breeze.App
+	t.Cleanup(func() {
+		breeze.App = previousApp
+	})
+	app := testkit.New(t, map[string]any{"breeze.encryption.key": "too-short"})
+	(&breeze.ServiceProvider{}).Register(app)
+
+	encrypter, err := Breeze().Encrypter()
+	assert.Nil(t, encrypter)
+	assert.ErrorContains(t, err, "32 bytes")
+}
+
+func TestBreezeBindsTheConfiguredEncrypter(t *testing.T) {
+	previousApp := breeze.App
+	t.Cleanup(func() {
+		breeze.App = previousApp
+	})
+	const previousKey = "breeze-previous-key-of-32-bytes!"
+	app := testkit.New(t, map[string]any{
+		"breeze.encryption.format":        "laravel",
+		"breeze.encryption.previous_keys": " " + previousKey + " ,",
+	})
+	(&breeze.ServiceProvider{}).Register(app)
+
+	instance, err := app.Make(breeze.Binding)
+	require.NoError(t, err)
+	assert.Implements(t, (*contracts.Breeze)(nil), instance)
+
+	bound, err := app.Make(breeze.EncrypterBinding)
+	require.NoError(t, err)
+	encrypter, err := Breeze().Encrypter()
+	require.NoError(t, err)
+	assert.Same(t, bound, encrypter)
+	assert.IsType(t, &crypt.LaravelEncrypter{}, encrypter)
+	assert.Equal(t, []string{testkit.Key, previousKey}, encrypter.GetAllKeys())
+
+	previous, err := crypt.NewLaravelEncrypter("aes-256-cbc", previousKey)
+	require.NoError(t, err)
+	payload, err := crypt.Encrypt(previous, map[string]string{"theme": "dark"})
+	require.NoError(t, err)
+	value, err := crypt.Decrypt[map[string]string](encrypter, payload)
+	require.NoError(t, err)
+	assert.Equal(t, "dark", value["theme"])
+}
@@ -0,0 +1,59 @@
+package breeze
+
+import (
+	"context"
+	"strings"
+
+	"github.com/goravel/framework/auth/access"
+	contractsaccess "github.com/goravel/framework/contracts/auth/access"
+	"github.com/goravel/framework/contracts/config"
+	"github.com/samehelhawary/goravel-breeze/app/models"
+)
+
+// gates holds the abilities Breeze checks. They are defined by the service
+// provider rather than a published provider, so publishing the assets again
+// with --force cannot remove them.
+type gates struct {
+	config config.Config
+}
+
+func (g *gates) define(gate contractsaccess.Gate) {
+	gate.Define("impersonate", g.impersonate)
+	gate.Define("invite", g.invite)
+}
+
+// impersonate allows the configured administrators to sign in as any user
+// that is not an administrator.
+func (g *gates) impersonate(ctx context.Context, arguments map[string]any) contractsaccess.Response {
+	impersonator, ok := arguments["impersonator"].(*models.User)
+	if !ok || !g.isAdmin(impersonator) {
+		return access.NewDenyResponse("You are not allowed to impersonate users.")
+	}
+
+	user, ok := arguments["user"].(*models.User)
+	if !ok || g.isAdmin(user) {
+		return access.NewDenyResponse("This user cannot be impersonated.")
+	}
+
+	return access.NewAllowResponse()
+}
+
+// invite allows the configured administrators to issue registration invitations.
+func (g *gates) invite(ctx context.Context, arguments map[string]any) contractsaccess.Response {
+	user, ok := arguments["user"].(*models.User)
+	if !ok || !g.isAdmin(user) {
+		return access.NewDenyResponse("You are not allowed to invite users.")
+	}
+
+	return access.NewAllowResponse()
+}
+
+func (g *gates) isAdmin(user *models.User) bool {
+	for _, email := range strings.Split(g.config.GetString("breeze.impersonation.admins"), ",") {
+		if email = strings.TrimSpace(email); email != "" && strings.EqualFold(email, user.Email) {
+			return true
+		}
+	}
+
+	return false
+}
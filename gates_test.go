@@ -0,0 +1,35 @@
+package breeze
+
+import (
+	"testing"
+
+	"github.com/samehelhawary/goravel-breeze/app/models"
+	"github.com/samehelhawary/goravel-breeze/testkit"
+)
+
+func TestServiceProviderDefinesGates(t *testing.T) {
+	app := testkit.New(t, map[string]any{"breeze.impersonation.admins": "admin@example.com, other@example.com"})
+	(&ServiceProvider{}).Boot(app)
+
+	admin := &models.User{Email: "Admin@Example.com"}
+	user := &models.User{Email: "user@example.com"}
+	otherAdmin := &models.User{Email: "other@example.com"}
+
+	tests := []struct {
+		ability   string
+		arguments map[string]any
+		allowed   bool
+	}{
+		{"impersonate", map[string]any{"impersonator": admin, "user": user}, true},
+		{"impersonate", map[string]any{"impersonator": user, "user": admin}, false},
+		{"impersonate", map[string]any{"impersonator": admin, "user": otherAdmin}, false},
+		{"impersonate", map[string]any{"user": user}, false},
+		{"invite", map[string]any{"user": admin}, true},
+		{"invite", map[string]any{"user": user}, false},
+	}
+	for _, test := range tests {
+		if allowed := app.MakeGate().Inspect(test.ability, test.arguments).Allowed(); allowed != test.allowed {
+			t.Errorf("%s %v: allowed = %v, want %v", test.ability, test.arguments, allowed, test.allowed)
+		}
+	}
+}
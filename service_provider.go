@@ -5,9 +5,13 @@ import (
 	"github.com/goravel/framework/contracts/console"
 	"github.com/goravel/framework/contracts/foundation"
 	"github.com/samehelhawary/goravel-breeze/console/commands"
+	"github.com/samehelhawary/goravel-breeze/crypt"
 )
 
-const Binding = "breeze"
+const (
+	Binding          = "breeze"
+	EncrypterBinding = "breeze.encrypter"
+)
 
 var App foundation.Application
 
@@ -19,7 +23,12 @@ func (receiver *ServiceProvider) Register(app foundation.Application) {
 	App = app
 
 	app.Bind(Binding, func(app foundation.Application) (any, error) {
-		return nil, nil
+		return &Breeze{app: app}, nil
+	})
+
+	// A single encrypter is shared, so every part of Breeze uses the same keys
+	app.Singleton(EncrypterBinding, func(app foundation.Application) (any, error) {
+		return crypt.NewFromConfig()
 	})
 
 	receiver.goravelFiberProvider = &fiber.ServiceProvider{}
@@ -38,6 +47,8 @@ func (receiver *ServiceProvider) Register(app foundation.Application) {
 	app.Commands([]console.Command{
 		&commands.Install{},
 		&commands.Migrate{},
+		&commands.Invite{},
+		&commands.Key{},
 	})
 }
 
@@ -50,4 +61,8 @@ func (receiver *ServiceProvider) Boot(app foundation.Application) {
 	if receiver.goravelFiberProvider != nil {
 		receiver.goravelFiberProvider.Boot(app)
 	}
+
+	if gate := app.MakeGate(); gate != nil {
+		(&gates{config: app.MakeConfig()}).define(gate)
+	}
 }
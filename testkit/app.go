@@ -0,0 +1,221 @@
+// Package testkit boots just enough of a Goravel application to exercise the
+// Breeze middleware and controllers on the real gin and fiber drivers,
+// with an in-memory cache and without a database or a .env file.
+package testkit
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	goravelfiber "github.com/goravel/fiber"
+	"github.com/goravel/framework/auth/access"
+	"github.com/goravel/framework/cache"
+	"github.com/goravel/framework/config"
+	contractsaccess "github.com/goravel/framework/contracts/auth/access"
+	contractscache "github.com/goravel/framework/contracts/cache"
+	contractsconfig "github.com/goravel/framework/contracts/config"
+	"github.com/goravel/framework/contracts/console"
+	"github.com/goravel/framework/contracts/foundation"
+	contractshttp "github.com/goravel/framework/contracts/http"
+	contractslog "github.com/goravel/framework/contracts/log"
+	"github.com/goravel/framework/contracts/validation"
+	frameworkfoundation "github.com/goravel/framework/foundation"
+	"github.com/goravel/framework/foundation/json"
+	goravelgin "github.com/goravel/gin"
+)
+
+// Key is the APP_KEY used by every test application.
+const Key = "breeze-testkit-app-key-32-bytes!"
+
+// App is a minimal foundation.Application: configuration, logging, views,
+// gates, an in-memory cache and a small container. Anything else panics, which makes unexpected
+// dependencies of the code under test obvious.
+type App struct {
+	foundation.Application
+
+	config *config.Application
+	log    *Log
+	view   *View
+	gate   *access.Gate
+	cache  contractscache.Cache
+
+	mu        sync.Mutex
+	bindings  map[any]func(app foundation.Application) (any, error)
+	singleton map[any]bool
+	instances map[any]any
+}
+
+// New creates an application with the given configuration and installs it as
+// the facade application for the duration of the test.
+func New(t testing.TB, settings map[string]any) *App {
+	t.Helper()
+	t.Setenv("APP_KEY", Key)
+
+	app := &App{
+		config:    config.NewApplication(""),
+		log:       NewLog(),
+		view:      NewView(),
+		gate:      access.NewGate(context.Background()),
+		bindings:  map[any]func(app foundation.Application) (any, error){},
+		singleton: map[any]bool{},
+		instances: map[any]any{},
+	}
+	for key, value := range map[string]any{
+		"app.name":              "Breeze",
+		"app.key":               Key,
+		"app.url":               "http://localhost",
+		"breeze.encryption.key": Key,
+		"cache.default":         "memory",
+		"cache.prefix":          "breeze",
+		"cache.stores.memory":   map[string]any{"driver": "memory"},
+		"session.lifetime":      120,
+		"session.path":          "/",
+		"session.http_only":     true,
+		"session.same_site":     "lax",
+	} {
+		app.config.Add(key, value)
+	}
+	for key, value := range settings {
+		app.config.Add(key, value)
+	}
+
+	previousApp := frameworkfoundation.App
+	ginConfig, ginLog, ginValidation, ginView := goravelgin.ConfigFacade, goravelgin.LogFacade, goravelgin.ValidationFacade, goravelgin.ViewFacade
+	fiberConfig, fiberLog, fiberValidation, fiberView := goravelfiber.ConfigFacade, goravelfiber.LogFacade, goravelfiber.ValidationFacade, goravelfiber.ViewFacade
+	t.Cleanup(func() {
+		frameworkfoundation.App = previousApp
+		goravelgin.ConfigFacade, goravelgin.LogFacade, goravelgin.ValidationFacade, goravelgin.ViewFacade = ginConfig, ginLog, ginValidation, ginView
+		goravelfiber.ConfigFacade, goravelfiber.LogFacade, goravelfiber.ValidationFacade, goravelfiber.ViewFacade = fiberConfig, fiberLog, fiberValidation, fiberView
+	})
+
+	frameworkfoundation.App = app
+	goravelgin.ConfigFacade, goravelgin.LogFacade, goravelgin.ViewFacade = app.config, app.log, app.view
+	goravelfiber.ConfigFacade, goravelfiber.LogFacade, goravelfiber.ViewFacade = app.config, app.log, app.view
+
+	return app
+}
+
+// Config returns the application configuration, for tests that change it
+// after the application has been created.
+func (a *App) Config() contractsconfig.Config {
+	return a.config
+}
+
+// Logs returns everything that has been logged so far.
+func (a *App) Logs() *Log {
+	return a.log
+}
+
+// View returns the view factory shared by both drivers.
+func (a *App) View() *View {
+	return a.view
+}
+
+func (a *App) MakeConfig() contractsconfig.Config {
+	return a.config
+}
+
+func (a *App) MakeLog() contractslog.Log {
+	return a.log
+}
+
+func (a *App) MakeView() contractshttp.View {
+	return a.view
+}
+
+func (a *App) MakeGate() contractsaccess.Gate {
+	return a.gate
+}
+
+// MakeCache returns the in-memory cache, created on first use.
+func (a *App) MakeCache() contractscache.Cache {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.cache == nil {
+		instance, err := cache.NewApplication(a.config, a.log, a.config.GetString("cache.default"))
+		if err != nil {
+			panic(err)
+		}
+		a.cache = instance
+	}
+
+	return a.cache
+}
+
+func (a *App) MakeValidation() validation.Validation {
+	return nil
+}
+
+func (a *App) GetJson() foundation.Json {
+	return json.NewJson()
+}
+
+func (a *App) Bind(key any, callback func(app foundation.Application) (any, error)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.bindings[key] = callback
+	delete(a.singleton, key)
+	delete(a.instances, key)
+}
+
+func (a *App) BindWith(key any, callback func(app foundation.Application, parameters map[string]any) (any, error)) {
+	a.Bind(key, func(app foundation.Application) (any, error) {
+		return callback(app, nil)
+	})
+}
+
+func (a *App) Singleton(key any, callback func(app foundation.Application) (any, error)) {
+	a.Bind(key, callback)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.singleton[key] = true
+}
+
+func (a *App) Instance(key, instance any) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.instances[key] = instance
+}
+
+func (a *App) Make(key any) (any, error) {
+	a.mu.Lock()
+	if instance, ok := a.instances[key]; ok {
+		a.mu.Unlock()
+		return instance, nil
+	}
+	callback, ok := a.bindings[key]
+	singleton := a.singleton[key]
+	a.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("binding was not found: %v", key)
+	}
+
+	instance, err := callback(a)
+	if err != nil {
+		return nil, err
+	}
+	if singleton {
+		a.Instance(key, instance)
+	}
+
+	return instance, nil
+}
+
+func (a *App) Publishes(string, map[string]string, ...string) {}
+
+func (a *App) Commands([]console.Command) {}
+
+func (a *App) BasePath(path ...string) string {
+	return filepath.Join(path...)
+}
+
+func (a *App) ConfigPath(path ...string) string {
+	return filepath.Join(append([]string{"config"}, path...)...)
+}
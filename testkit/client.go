@@ -0,0 +1,159 @@
+package testkit
+
+import (
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin/render"
+	"github.com/gofiber/fiber/v2"
+	goravelfiber "github.com/goravel/fiber"
+	"github.com/goravel/framework/contracts/route"
+	goravelgin "github.com/goravel/gin"
+)
+
+// Driver names an HTTP driver Breeze supports.
+type Driver string
+
+const (
+	Gin   Driver = "gin"
+	Fiber Driver = "fiber"
+)
+
+// Drivers lists every supported driver, for table driven tests.
+var Drivers = []Driver{Gin, Fiber}
+
+// BaseURL is the address requests of a Client are sent to.
+const BaseURL = "http://localhost"
+
+// Route creates an empty router on the given driver. Views are rendered with
+// Renderer.
+func (a *App) Route(t testing.TB, driver Driver) route.Route {
+	t.Helper()
+
+	var (
+		router route.Route
+		err    error
+	)
+	switch driver {
+	case Gin:
+		a.config.Add("http.drivers.gin.template", render.HTMLRender(Renderer{}))
+		router, err = goravelgin.NewRoute(a.config, map[string]any{"driver": string(driver)})
+	case Fiber:
+		a.config.Add("http.drivers.fiber.template", fiber.Views(Renderer{}))
+		router, err = goravelfiber.NewRoute(a.config, map[string]any{"driver": string(driver)})
+	default:
+		t.Fatalf("unknown driver %q", driver)
+	}
+	if err != nil {
+		t.Fatalf("failed to create %s route: %v", driver, err)
+	}
+
+	return router
+}
+
+// Client sends requests to a router and keeps the cookies it receives, like a
+// browser would.
+type Client struct {
+	t      testing.TB
+	router route.Route
+	jar    *cookiejar.Jar
+}
+
+func NewClient(t testing.TB, router route.Route) *Client {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &Client{t: t, router: router, jar: jar}
+}
+
+// Response is a received response with its body already read.
+type Response struct {
+	*http.Response
+	Body string
+}
+
+// Cookie returns the value the cookie jar holds for name.
+func (c *Client) Cookie(name string) string {
+	base, _ := url.Parse(BaseURL)
+	for _, cookie := range c.jar.Cookies(base) {
+		if cookie.Name == name {
+			return cookie.Value
+		}
+	}
+
+	return ""
+}
+
+// SetCookie stores a cookie as if an earlier response had set it.
+func (c *Client) SetCookie(cookie *http.Cookie) {
+	base, _ := url.Parse(BaseURL)
+	c.jar.SetCookies(base, []*http.Cookie{cookie})
+}
+
+// NewRequest builds a request for path on BaseURL. Its request URI is the
+// path alone, as servers receive it, so FullUrl is the same on both drivers.
+func NewRequest(method, path string, body io.Reader) *http.Request {
+	request := httptest.NewRequest(method, BaseURL+path, body)
+	request.RequestURI = request.URL.RequestURI()
+
+	return request
+}
+
+// Do sends the request with the stored cookies and stores the cookies of the
+// response.
+func (c *Client) Do(request *http.Request) *Response {
+	c.t.Helper()
+
+	for _, cookie := range c.jar.Cookies(request.URL) {
+		request.AddCookie(cookie)
+	}
+
+	response, err := c.router.Test(request)
+	if err != nil {
+		c.t.Fatalf("%s %s failed: %v", request.Method, request.URL.Path, err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		c.t.Fatalf("failed to read the response of %s %s: %v", request.Method, request.URL.Path, err)
+	}
+	c.jar.SetCookies(request.URL, response.Cookies())
+
+	return &Response{Response: response, Body: string(body)}
+}
+
+// Get sends a GET request.
+func (c *Client) Get(path string) *Response {
+	c.t.Helper()
+
+	return c.Do(NewRequest(http.MethodGet, path, nil))
+}
+
+// PostForm sends a form encoded POST request.
+func (c *Client) PostForm(path string, form url.Values) *Response {
+	c.t.Helper()
+
+	request := NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return c.Do(request)
+}
+
+// PostJSON sends a POST request with a JSON body.
+func (c *Client) PostJSON(path string, body string) *Response {
+	c.t.Helper()
+
+	request := NewRequest(http.MethodPost, path, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+
+	return c.Do(request)
+}
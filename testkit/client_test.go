@@ -0,0 +1,40 @@
+package testkit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/goravel/framework/contracts/http"
+)
+
+func TestClientKeepsSessionAndRendersViews(t *testing.T) {
+	for _, driver := range Drivers {
+		t.Run(string(driver), func(t *testing.T) {
+			app := New(t, nil)
+			sessions := NewSessions(map[string]any{"greeting": "hello"})
+			router := app.Route(t, driver)
+			router.Middleware(sessions.Middleware()).Get("/count", func(ctx http.Context) http.Response {
+				count, _ := ctx.Request().Session().Get("count", float64(0)).(float64)
+				ctx.Request().Session().Put("count", count+1)
+
+				return ctx.Response().View().Make("count", map[string]any{
+					"count":    count + 1,
+					"greeting": ctx.Request().Session().Get("greeting"),
+					"shout":    func() string { return "HI" },
+				})
+			})
+
+			client := NewClient(t, router)
+			client.Get("/count")
+			response := client.Get("/count")
+
+			want := "view: count\ncount: 2\ngreeting: hello\nshout: HI\n"
+			if response.StatusCode != http.StatusOK || response.Body != want {
+				t.Fatalf("got %d %q, want %q", response.StatusCode, response.Body, want)
+			}
+			if !strings.Contains(response.Header.Get("Content-Type"), "text/html") {
+				t.Errorf("content type = %q", response.Header.Get("Content-Type"))
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package testkit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	contractshttp "github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/contracts/log"
+)
+
+// Entry is one recorded log line.
+type Entry struct {
+	Level   log.Level
+	Message string
+	Data    map[string]any
+}
+
+// Log records log lines instead of writing them anywhere.
+type Log struct {
+	log.Writer
+
+	mu      sync.Mutex
+	entries []Entry
+}
+
+func NewLog() *Log {
+	l := &Log{}
+	l.Writer = &writer{log: l}
+
+	return l
+}
+
+// Entries returns the lines logged so far.
+func (l *Log) Entries() []Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return append([]Entry(nil), l.entries...)
+}
+
+// Contains reports whether a line at the given level contains text.
+func (l *Log) Contains(level log.Level, text string) bool {
+	for _, entry := range l.Entries() {
+		if entry.Level == level && strings.Contains(entry.Message, text) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (l *Log) WithContext(context.Context) log.Writer { return &writer{log: l} }
+
+func (l *Log) Channel(string) log.Writer { return &writer{log: l} }
+
+func (l *Log) Stack([]string) log.Writer { return &writer{log: l} }
+
+// writer carries the fields added with With until a line is written.
+type writer struct {
+	log  *Log
+	data map[string]any
+}
+
+func (w *writer) write(level log.Level, message string) {
+	w.log.mu.Lock()
+	defer w.log.mu.Unlock()
+
+	w.log.entries = append(w.log.entries, Entry{Level: level, Message: message, Data: w.data})
+}
+
+func (w *writer) Debug(args ...any) { w.write(log.DebugLevel, fmt.Sprint(args...)) }
+func (w *writer) Debugf(format string, args ...any) {
+	w.write(log.DebugLevel, fmt.Sprintf(format, args...))
+}
+func (w *writer) Info(args ...any) { w.write(log.InfoLevel, fmt.Sprint(args...)) }
+func (w *writer) Infof(format string, args ...any) {
+	w.write(log.InfoLevel, fmt.Sprintf(format, args...))
+}
+func (w *writer) Warning(args ...any) { w.write(log.WarningLevel, fmt.Sprint(args...)) }
+func (w *writer) Warningf(format string, args ...any) {
+	w.write(log.WarningLevel, fmt.Sprintf(format, args...))
+}
+func (w *writer) Error(args ...any) { w.write(log.ErrorLevel, fmt.Sprint(args...)) }
+func (w *writer) Errorf(format string, args ...any) {
+	w.write(log.ErrorLevel, fmt.Sprintf(format, args...))
+}
+func (w *writer) Fatal(args ...any) { w.write(log.FatalLevel, fmt.Sprint(args...)) }
+func (w *writer) Fatalf(format string, args ...any) {
+	w.write(log.FatalLevel, fmt.Sprintf(format, args...))
+}
+func (w *writer) Panic(args ...any) { w.write(log.PanicLevel, fmt.Sprint(args...)) }
+func (w *writer) Panicf(format string, args ...any) {
+	w.write(log.PanicLevel, fmt.Sprintf(format, args...))
+}
+
+func (w *writer) Code(string) log.Writer                            { return w }
+func (w *writer) Hint(string) log.Writer                            { return w }
+func (w *writer) In(string) log.Writer                              { return w }
+func (w *writer) Owner(any) log.Writer                              { return w }
+func (w *writer) Request(contractshttp.ContextRequest) log.Writer   { return w }
+func (w *writer) Response(contractshttp.ContextResponse) log.Writer { return w }
+func (w *writer) Tags(...string) log.Writer                         { return w }
+func (w *writer) User(any) log.Writer                               { return w }
+func (w *writer) WithTrace() log.Writer                             { return w }
+
+func (w *writer) With(data map[string]any) log.Writer {
+	merged := make(map[string]any, len(w.data)+len(data))
+	for key, value := range w.data {
+		merged[key] = value
+	}
+	for key, value := range data {
+		merged[key] = value
+	}
+
+	return &writer{log: w.log, data: merged}
+}
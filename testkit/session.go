@@ -0,0 +1,104 @@
+package testkit
+
+import (
+	"sync"
+
+	contractshttp "github.com/goravel/framework/contracts/http"
+	contractssession "github.com/goravel/framework/contracts/session"
+	"github.com/goravel/framework/foundation/json"
+	"github.com/goravel/framework/session"
+)
+
+// SessionCookie is the cookie the test sessions are tracked with.
+const SessionCookie = "goravel_session"
+
+// Sessions is an in-memory session driver with a middleware starting the
+// session, so session values survive between the requests of a Client the
+// same way they do with a real driver: serialized as JSON.
+type Sessions struct {
+	mu      sync.Mutex
+	data    map[string]string
+	seed    map[string]any
+	current contractssession.Session
+}
+
+// NewSessions creates a session store. Every new session starts with seed.
+func NewSessions(seed map[string]any) *Sessions {
+	return &Sessions{data: map[string]string{}, seed: seed}
+}
+
+// Middleware starts the session of the request, like the framework's
+// StartSession middleware.
+func (s *Sessions) Middleware() contractshttp.Middleware {
+	return func(ctx contractshttp.Context) {
+		started := session.NewSession(SessionCookie, s, json.NewJson())
+		started.SetID(ctx.Request().Cookie(SessionCookie))
+		started.Start()
+		if started.GetID() != ctx.Request().Cookie(SessionCookie) {
+			for key, value := range s.seed {
+				started.Put(key, value)
+			}
+		}
+		ctx.Request().SetSession(started)
+
+		ctx.Response().Cookie(contractshttp.Cookie{
+			Name:     SessionCookie,
+			Value:    started.GetID(),
+			Path:     "/",
+			HttpOnly: true,
+		})
+
+		ctx.Request().Next()
+
+		_ = started.Save()
+
+		s.mu.Lock()
+		s.current = started
+		s.mu.Unlock()
+	}
+}
+
+// Current returns the session of the last request.
+func (s *Sessions) Current() contractssession.Session {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.current
+}
+
+func (s *Sessions) Close() error {
+	return nil
+}
+
+func (s *Sessions) Destroy(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.data, id)
+
+	return nil
+}
+
+func (s *Sessions) Gc(int) error {
+	return nil
+}
+
+func (s *Sessions) Open(string, string) error {
+	return nil
+}
+
+func (s *Sessions) Read(id string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.data[id], nil
+}
+
+func (s *Sessions) Write(id string, data string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data[id] = data
+
+	return nil
+}
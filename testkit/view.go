@@ -0,0 +1,133 @@
+package testkit
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin/render"
+)
+
+// View is the view factory of the test application. Every view exists, and
+// is rendered by Renderer.
+type View struct {
+	mu     sync.Mutex
+	shared map[string]any
+}
+
+func NewView() *View {
+	return &View{shared: map[string]any{}}
+}
+
+func (v *View) Exists(string) bool {
+	return true
+}
+
+func (v *View) Share(key string, value any) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.shared[key] = value
+}
+
+func (v *View) Shared(key string, def ...any) any {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if value, ok := v.shared[key]; ok {
+		return value
+	}
+	if len(def) > 0 {
+		return def[0]
+	}
+
+	return nil
+}
+
+func (v *View) GetShared() map[string]any {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	shared := make(map[string]any, len(v.shared))
+	for key, value := range v.shared {
+		shared[key] = value
+	}
+
+	return shared
+}
+
+// Renderer stands in for the Jet templates: it writes the view name followed
+// by one "key: value" line per scalar in the view data, sorted by key.
+// Functions without arguments, like csrf_field, are called and their result
+// is written instead.
+type Renderer struct{}
+
+// Render satisfies fiber.Views.
+func (Renderer) Render(out io.Writer, view string, data any, _ ...string) error {
+	_, err := io.WriteString(out, RenderView(view, data))
+
+	return err
+}
+
+// Load satisfies fiber.Views.
+func (Renderer) Load() error {
+	return nil
+}
+
+// Instance satisfies gin's render.HTMLRender.
+func (Renderer) Instance(view string, data any) render.Render {
+	return ginRender{view: view, data: data}
+}
+
+type ginRender struct {
+	view string
+	data any
+}
+
+func (r ginRender) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+	_, err := io.WriteString(w, RenderView(r.view, r.data))
+
+	return err
+}
+
+func (r ginRender) WriteContentType(w http.ResponseWriter) {
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	}
+}
+
+// RenderView is the output Renderer produces for a view and its data.
+func RenderView(view string, data any) string {
+	lines := []string{"view: " + view}
+
+	value := reflect.ValueOf(data)
+	if value.Kind() == reflect.Map {
+		var fields []string
+		for _, key := range value.MapKeys() {
+			item := value.MapIndex(key)
+			if item.Kind() == reflect.Interface {
+				item = item.Elem()
+			}
+			if item.Kind() == reflect.Func && item.Type().NumIn() == 0 && item.Type().NumOut() == 1 && !item.IsNil() {
+				item = item.Call(nil)[0]
+			}
+
+			switch item.Kind() {
+			case reflect.String, reflect.Bool,
+				reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+				reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+				reflect.Float32, reflect.Float64:
+				fields = append(fields, fmt.Sprintf("%v: %v", key.Interface(), item.Interface()))
+			}
+		}
+		sort.Strings(fields)
+		lines = append(lines, fields...)
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}